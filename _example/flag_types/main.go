@@ -1,6 +1,7 @@
 package main
 
 import (
+	"encoding/json"
 	"fmt"
 
 	"github.com/fritzkeyzer/cli"
@@ -18,11 +19,33 @@ var countFlag = &cli.IntFlag{
 	Value: 5, // default value, since countFlag is optional
 }
 
-var verboseFlag = &cli.BoolFlag{
+// verboseFlag demonstrates a CountFlag: -v, -vv, -vvv or --verbose=3
+var verboseFlag = &cli.CountFlag{
 	Name:  "verbose",
 	Alias: "v",
+	Max:   3,
 }
 
+// formatFlag demonstrates an EnumFlag, mapping tokens to typed values
+var formatFlag = &cli.EnumFlag[Format]{
+	Name:        "format",
+	Alias:       "f",
+	Description: "Output format",
+	Values: []cli.EnumValue[Format]{
+		{Token: "text", Value: FormatText, Description: "Human readable text"},
+		{Token: "json", Value: FormatJSON, Description: "JSON, for use in scripts"},
+	},
+	CaseInsensitive: true,
+	Value:           FormatText, // default value
+}
+
+type Format int
+
+const (
+	FormatText Format = iota
+	FormatJSON
+)
+
 // personFlag demonstrates a generic JSONFlag, using the Person type
 var personFlag = &cli.JSONFlag[Person]{
 	Name:        "person",
@@ -78,13 +101,19 @@ var personCmd = cli.Cmd{
 	Name:        "person",
 	Description: "Print the name and age of a person. (Demonstrates usage of a generic JSONFlag)",
 	ReqFlags:    []cli.Flag{personFlag},
-	OptFlags:    []cli.Flag{verboseFlag},
+	OptFlags:    []cli.Flag{verboseFlag, formatFlag},
 	Action: func(args map[string]string) {
 		person := personFlag.Value
 		verbose := verboseFlag.Value
 
+		if formatFlag.Value == FormatJSON {
+			out, _ := json.Marshal(person)
+			fmt.Println(string(out))
+			return
+		}
+
 		fmt.Println("Person:", person)
-		if verbose {
+		if verbose > 0 {
 			fmt.Println("Age:", person.Age)
 		}
 	},
@@ -35,6 +35,7 @@ var GCPCredsFlag = &cli.JSONFlag[GCPCreds]{
 	Name:        "gcp-creds",
 	EnvVar:      "GCP_CREDS",
 	Description: "GCP credentials in JSON format",
+	Secret:      true, // the credentials contain a private key, never print them
 }
 
 var BackupBucketFlag = &cli.StringFlag{
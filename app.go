@@ -13,21 +13,142 @@ type App struct {
 	OptFlags    []Flag   // optional flags: if not provided, the default value will be used. Note that the help flag is automatically added to this list.
 	Args        []string // positional args: used to populate the args map passed to the action function.
 	Action      func(args map[string]string)
+	// ActionCtx is used instead of Action, if specified. The context reports where each flag value was loaded from.
+	ActionCtx func(ctx *Context) error
+
+	FlagConstraints []FlagConstraint // constraints between flags, eg: MutuallyExclusive(fileFlag, urlFlag)
+
+	// AutoEnv derives env var names for all flags that implement EnvVarFlag but don't specify an env var,
+	// from the EnvPrefix and the flag name, eg: MYAPP_DB_CONN. The flags are not modified.
+	AutoEnv bool
+	// AutoEnvCmdPath includes the command path in derived env var names, eg: MYAPP_DB_MIGRATE_STEPS.
+	AutoEnvCmdPath bool
+	// EnvPrefix is the prefix used for derived env var names, eg: MYAPP.
+	EnvPrefix string
+
+	// ConfigFile is the path to a JSON config file, used for flags not provided by args or env vars.
+	// If specified, the file must exist. Env vars and ~ are expanded.
+	// Line comments (//) and trailing commas are allowed in the file, see ConfigTemplateCmd.
+	ConfigFile string
+	// ConfigSearchPaths are used if ConfigFile is not specified: the first file that exists is used, if any.
+	// Eg: []string{"./myapp.json", cli.UserConfigPath("myapp")}
+	ConfigSearchPaths []string
+
+	// DotEnvFiles are dotenv files read before flags are loaded, eg: []string{".env"}.
+	// Their env vars are read like any other env var (see LookupEnv), without modifying the environment of the process.
+	// Files that don't exist are skipped. Env vars that are already set are not overridden.
+	// See also DotEnvSource, which is a value source with its own precedence.
+	DotEnvFiles []string
+	// DotEnvOverride allows dotenv files to override env vars that are already set.
+	DotEnvOverride bool
+
+	// ConfigTemplateCmd adds a "config-template" command, which prints a config file template listing every flag.
+	ConfigTemplateCmd bool
+
+	// Profiles enables named sets of flag values, stored as files in ProfileDir.
+	// A profile is selected with the --profile flag or ProfileEnvVar, and takes precedence over the config file.
+	// A "profile" command is added to list, show and set profile values.
+	Profiles bool
+	// ProfileDir defaults to <user config dir>/<app>/profiles, eg: $XDG_CONFIG_HOME/myapp/profiles on linux.
+	ProfileDir string
+	// ProfileEnvVar selects a profile, defaults to <EnvPrefix>_PROFILE (or <Name>_PROFILE if EnvPrefix is not set).
+	ProfileEnvVar string
+
+	// ValueSources are where flag values are loaded from, in order of precedence.
+	// Defaults to ArgsSource, EnvSource, ProfileSource and ConfigSource.
+	// Eg: []cli.ValueSource{cli.ArgsSource(), cli.EnvSource(), cli.DotEnvSource(".env"), vaultSource}
+	ValueSources []ValueSource
+
+	// LookupEnv is used to read env vars, eg: cli.MapEnv(map[string]string{"DB_CONN": "postgres://test"}) in tests.
+	// Defaults to os.LookupEnv.
+	LookupEnv func(name string) (string, bool)
+
+	config       *configFile // for internal use only, the loaded config file
+	configLoaded bool
+	dotEnv       map[string]string // for internal use only, the env vars of DotEnvFiles
 }
 
+// Run runs the app with the args of the process, and exits if there is an error
 func (app *App) Run() {
+	if err := app.RunArgs(os.Args[1:]); err != nil {
+		log.Fatal("ERROR: ", err)
+	}
+}
+
+// RunArgs runs the app with args, excluding the name of the executable. Eg: in tests:
+//
+//	err := app.RunArgs([]string{"serve", "--port=8080"})
+func (app *App) RunArgs(args []string) error {
 	rootCmd := Cmd{
 		Name:        app.Name,
 		Args:        app.Args,
 		Description: app.Description,
-		SubCmds:     app.SubCmds,
+		SubCmds:     app.subCmds(),
 		ReqFlags:    app.ReqFlags,
 		OptFlags:    app.OptFlags,
 		Action:      app.Action,
+		ActionCtx:   app.ActionCtx,
+
+		FlagConstraints: app.FlagConstraints,
+
+		app: app,
 	}
 
-	err := rootCmd.run(os.Args[1:], []string{app.Name})
+	dotEnv, err := loadDotEnv(app.DotEnvFiles, app.DotEnvOverride, app.lookupProcessEnv)
 	if err != nil {
-		log.Fatal("ERROR: ", err)
+		return err
+	}
+	app.dotEnv = dotEnv
+
+	return rootCmd.run(args, []string{app.Name})
+}
+
+// lookupEnv reads an env var from DotEnvFiles and LookupEnv
+func (app *App) lookupEnv(name string) (string, bool) {
+	if app.dotEnv == nil {
+		return app.lookupProcessEnv(name)
 	}
+
+	return dotEnvLookup(app.dotEnv, app.DotEnvOverride, app.lookupProcessEnv)(name)
+}
+
+// lookupProcessEnv reads an env var with LookupEnv, or os.LookupEnv
+func (app *App) lookupProcessEnv(name string) (string, bool) {
+	if app.LookupEnv != nil {
+		return app.LookupEnv(name)
+	}
+
+	return os.LookupEnv(name)
+}
+
+// loadConfig loads the config file once, returns nil if there is no config file
+func (app *App) loadConfig() (*configFile, error) {
+	if app.configLoaded {
+		return app.config, nil
+	}
+
+	config, err := loadConfigFile(app.ConfigFile, app.ConfigSearchPaths, app.lookupEnv)
+	if err != nil {
+		return nil, err
+	}
+
+	app.config = config
+	app.configLoaded = true
+
+	return config, nil
+}
+
+// subCmds returns the sub commands of the app, including built-in commands
+func (app *App) subCmds() []Cmd {
+	subCmds := append([]Cmd{}, app.SubCmds...)
+
+	if app.ConfigTemplateCmd {
+		subCmds = append(subCmds, configTemplateCmd(app))
+	}
+
+	if app.Profiles {
+		subCmds = append(subCmds, profileCmd(app))
+	}
+
+	return subCmds
 }
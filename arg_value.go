@@ -0,0 +1,87 @@
+package cli
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strings"
+)
+
+// argValueResolver replaces values that reference a file or stdin with their contents,
+// for flags that allow it (see FromFile):
+//
+//	--flag=@path    the contents of the file at path
+//	--flag=-        the contents of stdin
+//	--flag=@@value  the literal value '@value'
+//
+// Stdin can only be consumed by a single flag.
+type argValueResolver struct {
+	stdin     io.Reader
+	stdinFlag string // name of the flag that consumed stdin, if any
+}
+
+func (r *argValueResolver) resolve(fl Flag, val *string) (*string, error) {
+	if val == nil {
+		return nil, nil
+	}
+
+	if ff, ok := flagAs[FileValueFlag](fl); !ok || !ff.ValueFromFile() {
+		return val, nil
+	}
+
+	switch {
+	case strings.HasPrefix(*val, "@@"):
+		literal := strings.TrimPrefix(*val, "@")
+		return &literal, nil
+
+	case strings.HasPrefix(*val, "@"):
+		path := strings.TrimPrefix(*val, "@")
+		contents, err := readValueFile(path)
+		if err != nil {
+			return nil, err
+		}
+
+		return &contents, nil
+
+	case *val == "-":
+		stdin, err := r.openStdin(fl.GetName())
+		if err != nil {
+			return nil, err
+		}
+
+		b, err := io.ReadAll(stdin)
+		if err != nil {
+			return nil, fmt.Errorf("reading stdin: %w", err)
+		}
+
+		contents := trimTrailingNewline(string(b))
+		return &contents, nil
+	}
+
+	return val, nil
+}
+
+// openStdin returns stdin for the named flag. Stdin can only be consumed by a single flag.
+func (r *argValueResolver) openStdin(name string) (io.Reader, error) {
+	if r.stdinFlag != "" {
+		return nil, fmt.Errorf("stdin has already been read by flag '%s'", r.stdinFlag)
+	}
+	r.stdinFlag = name
+
+	return r.stdin, nil
+}
+
+// readValueFile reads a file containing a flag value. A single trailing newline is removed.
+func readValueFile(path string) (string, error) {
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return "", fmt.Errorf("reading value from file: %w", err)
+	}
+
+	return trimTrailingNewline(string(b)), nil
+}
+
+func trimTrailingNewline(s string) string {
+	s = strings.TrimSuffix(s, "\n")
+	return strings.TrimSuffix(s, "\r")
+}
@@ -0,0 +1,105 @@
+package cli
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func Test_argValueResolver_resolve(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "creds.json")
+	if err := os.WriteFile(path, []byte(`{"key":"value"}`+"\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name    string
+		flag    Flag
+		val     *string
+		want    *string
+		wantErr bool
+	}{
+		{
+			name: "nil",
+			flag: FromFile(&StringFlag{Name: "creds"}),
+			val:  nil,
+			want: nil,
+		},
+		{
+			name: "plain value",
+			flag: FromFile(&StringFlag{Name: "creds"}),
+			val:  s("value"),
+			want: s("value"),
+		},
+		{
+			name: "file",
+			flag: FromFile(&StringFlag{Name: "creds"}),
+			val:  s("@" + path),
+			want: s(`{"key":"value"}`),
+		},
+		{
+			name: "escaped @",
+			flag: FromFile(&StringFlag{Name: "creds"}),
+			val:  s("@@value"),
+			want: s("@value"),
+		},
+		{
+			name: "stdin",
+			flag: FromFile(&StringFlag{Name: "creds"}),
+			val:  s("-"),
+			want: s("from stdin"),
+		},
+		{
+			name:    "missing file",
+			flag:    FromFile(&StringFlag{Name: "creds"}),
+			val:     s("@" + path + ".missing"),
+			wantErr: true,
+		},
+		{
+			name: "not from file",
+			flag: &StringFlag{Name: "user"},
+			val:  s("@handle"),
+			want: s("@handle"),
+		},
+		{
+			name: "stdin not from file",
+			flag: &FileFlag{Name: "creds"},
+			val:  s("-"),
+			want: s("-"),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := &argValueResolver{stdin: strings.NewReader("from stdin\n")}
+
+			got, err := r.resolve(tt.flag, tt.val)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("resolve() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("resolve() got = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_argValueResolver_stdinOnce(t *testing.T) {
+	r := &argValueResolver{stdin: strings.NewReader("from stdin")}
+
+	if _, err := r.resolve(FromFile(&StringFlag{Name: "a"}), s("-")); err != nil {
+		t.Fatalf("resolve() unexpected error = %v", err)
+	}
+
+	if _, err := r.resolve(FromFile(&StringFlag{Name: "b"}), s("-")); err == nil {
+		t.Errorf("resolve() expected error when stdin is used twice")
+	}
+
+	// FileFlag reads stdin through the same resolver
+	fileFlag := &FileFlag{Name: "input", Read: true}
+	if _, err := fileFlag.loadWith(loadContext{stdin: r.openStdin}, true, s("-")); err == nil {
+		t.Errorf("loadWith() expected error when stdin is used twice")
+	}
+}
@@ -0,0 +1,379 @@
+package cli
+
+import (
+	"encoding"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/netip"
+	"net/url"
+	"reflect"
+	"strings"
+	"time"
+	"unicode"
+)
+
+// Bind creates flags for the fields of a struct, which are populated when the flags are loaded by Cmd.run.
+// The current values of the fields are used as defaults. ptr must be a pointer to a struct.
+//
+// Fields are configured with the cli and desc tags:
+//
+//	type Config struct {
+//	    DBConn  string        `cli:"db-conn,alias=d,env=DB_CONN,required" desc:"Database connection string"`
+//	    Timeout time.Duration // --timeout
+//	    Tags    []string      `cli:",env=TAGS"`
+//	    Verbose int           `cli:"verbose,alias=v,count"`
+//	    Token   string        `cli:",secret"`
+//	    Debug   bool          `cli:",hidden"`
+//	    Creds   string        `cli:",file"`      // --creds=@creds.json, see FromFile
+//	    Skip    string        `cli:"-"`
+//	    DB      struct {
+//	        Host string     // --db-host
+//	    }
+//	}
+//
+// The name defaults to the field name in kebab case, eg: DBConn => db-conn.
+// Nested structs add a prefix to the names of their fields (the name of the struct field),
+// unless the struct is embedded.
+//
+// Supported field types are: string, bool, int, int64, uint, time.Duration, []string, map[string]string,
+// url.URL, *url.URL, netip.Addr and netip.Prefix. Other types that implement encoding.TextUnmarshaler,
+// eg: time.Time, are loaded as text. Other slices and maps are loaded as JSON.
+func Bind(ptr any) (reqFlags []Flag, optFlags []Flag, err error) {
+	v := reflect.ValueOf(ptr)
+	if v.Kind() != reflect.Pointer || v.IsNil() || v.Elem().Kind() != reflect.Struct {
+		return nil, nil, fmt.Errorf("bind: expected a pointer to a struct, got %T", ptr)
+	}
+
+	b := &binder{}
+	if err := b.bindStruct(v.Elem(), ""); err != nil {
+		return nil, nil, fmt.Errorf("bind: %w", err)
+	}
+
+	return b.reqFlags, b.optFlags, nil
+}
+
+// FlagsFrom adds the flags created by Bind to the command.
+// An error is returned if ptr is not a pointer to a struct, or if the struct has invalid tags or unsupported fields.
+func (cmd Cmd) FlagsFrom(ptr any) (Cmd, error) {
+	reqFlags, optFlags, err := Bind(ptr)
+	if err != nil {
+		return cmd, err
+	}
+
+	cmd.ReqFlags = append(append([]Flag{}, cmd.ReqFlags...), reqFlags...)
+	cmd.OptFlags = append(append([]Flag{}, cmd.OptFlags...), optFlags...)
+
+	return cmd, nil
+}
+
+// boundValueFlag is implemented by flags created by Bind, to set the struct field after the flag is loaded
+type boundValueFlag interface {
+	bindValue() error
+}
+
+// boundFlag sets a struct field to the value of the wrapped flag
+type boundFlag struct {
+	Flag
+	bind func() error
+}
+
+func (flag *boundFlag) bindValue() error {
+	return flag.bind()
+}
+
+// Unwrap returns the wrapped flag
+func (flag *boundFlag) Unwrap() Flag {
+	return flag.Flag
+}
+
+type binder struct {
+	reqFlags []Flag
+	optFlags []Flag
+}
+
+// bindTag holds the options of a cli struct tag
+type bindTag struct {
+	name     string
+	alias    string
+	envVar   string
+	required bool
+	hidden   bool
+	secret   bool
+	count    bool
+	file     bool
+}
+
+func (b *binder) bindStruct(v reflect.Value, prefix string) error {
+	t := v.Type()
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		// fields of embedded structs are promoted, even if the struct type is unexported
+		if !field.IsExported() && !(field.Anonymous && field.Type.Kind() == reflect.Struct) {
+			continue
+		}
+
+		tagStr, hasTag := field.Tag.Lookup("cli")
+		if tagStr == "-" {
+			continue
+		}
+
+		tag, err := parseBindTag(tagStr)
+		if err != nil {
+			return fmt.Errorf("field %s: %w", field.Name, err)
+		}
+
+		name := tag.name
+		if name == "" {
+			name = kebabCase(field.Name)
+		}
+
+		if field.Type.Kind() == reflect.Struct && !isLeafType(field.Type) {
+			nestedPrefix := prefix + name + "-"
+			if field.Anonymous && !hasTag {
+				nestedPrefix = prefix
+			}
+
+			if err := b.bindStruct(v.Field(i), nestedPrefix); err != nil {
+				return err
+			}
+			continue
+		}
+
+		tag.name = prefix + name
+		fl, err := bindField(v.Field(i), tag, field.Tag.Get("desc"))
+		if err != nil {
+			return fmt.Errorf("field %s: %w", field.Name, err)
+		}
+
+		if tag.file {
+			fl = FromFile(fl)
+		}
+
+		if tag.hidden {
+			fl = Hide(fl)
+		}
+
+		if tag.required {
+			b.reqFlags = append(b.reqFlags, fl)
+		} else {
+			b.optFlags = append(b.optFlags, fl)
+		}
+	}
+
+	return nil
+}
+
+func parseBindTag(tag string) (bindTag, error) {
+	parts := strings.Split(tag, ",")
+
+	t := bindTag{name: strings.TrimSpace(parts[0])}
+	for _, opt := range parts[1:] {
+		key, val, _ := strings.Cut(strings.TrimSpace(opt), "=")
+
+		switch key {
+		case "alias":
+			t.alias = val
+		case "env":
+			t.envVar = val
+		case "required":
+			t.required = true
+		case "hidden":
+			t.hidden = true
+		case "secret":
+			t.secret = true
+		case "count":
+			t.count = true
+		case "file":
+			t.file = true
+		case "":
+		default:
+			return t, fmt.Errorf("unknown cli tag option '%s'", key)
+		}
+	}
+
+	return t, nil
+}
+
+var (
+	durationType        = reflect.TypeOf(time.Duration(0))
+	stringSliceType     = reflect.TypeOf([]string(nil))
+	stringMapType       = reflect.TypeOf(map[string]string(nil))
+	urlType             = reflect.TypeOf(url.URL{})
+	urlPtrType          = reflect.TypeOf(&url.URL{})
+	addrType            = reflect.TypeOf(netip.Addr{})
+	prefixType          = reflect.TypeOf(netip.Prefix{})
+	textUnmarshalerType = reflect.TypeOf((*encoding.TextUnmarshaler)(nil)).Elem()
+)
+
+// isLeafType returns true if a struct type is bound to a single flag, instead of a flag per field,
+// eg: url.URL or time.Time
+func isLeafType(t reflect.Type) bool {
+	return t == urlType || reflect.PointerTo(t).Implements(textUnmarshalerType)
+}
+
+// bindField creates a flag for the struct field
+func bindField(field reflect.Value, tag bindTag, desc string) (Flag, error) {
+	t := field.Type()
+
+	if tag.secret && t.Kind() != reflect.String {
+		return nil, errors.New("secret is only supported for string fields")
+	}
+	if tag.count && t.Kind() != reflect.Int {
+		return nil, errors.New("count is only supported for int fields")
+	}
+
+	var fl Flag
+	var bind func() error
+
+	switch {
+	case t == durationType:
+		f := &DurationFlag{Value: time.Duration(field.Int())}
+		fl, bind = f, func() error { field.SetInt(int64(f.Value)); return nil }
+
+	case t == urlType:
+		f := &URLFlag{}
+		if !field.IsZero() {
+			u := field.Interface().(url.URL)
+			f.Value = &u
+		}
+		fl, bind = f, func() error {
+			if f.Value != nil {
+				field.Set(reflect.ValueOf(*f.Value))
+			}
+			return nil
+		}
+
+	case t == urlPtrType:
+		f := &URLFlag{Value: field.Interface().(*url.URL)}
+		fl, bind = f, func() error { field.Set(reflect.ValueOf(f.Value)); return nil }
+
+	case t == addrType:
+		f := &IPFlag{Value: field.Interface().(netip.Addr)}
+		fl, bind = f, func() error { field.Set(reflect.ValueOf(f.Value)); return nil }
+
+	case t == prefixType:
+		f := &CIDRFlag{Value: field.Interface().(netip.Prefix)}
+		fl, bind = f, func() error { field.Set(reflect.ValueOf(f.Value)); return nil }
+
+	case reflect.PointerTo(t).Implements(textUnmarshalerType) && !tag.secret && !tag.count:
+		f, err := bindTextField(field)
+		if err != nil {
+			return nil, err
+		}
+		fl, bind = f, func() error {
+			if f.Value == "" {
+				return nil
+			}
+			return field.Addr().Interface().(encoding.TextUnmarshaler).UnmarshalText([]byte(f.Value))
+		}
+
+	case t.Kind() == reflect.String && tag.secret:
+		f := &SecretFlag{Value: field.String()}
+		fl, bind = f, func() error { field.SetString(f.Value); return nil }
+
+	case t.Kind() == reflect.String:
+		f := &StringFlag{Value: field.String()}
+		fl, bind = f, func() error { field.SetString(f.Value); return nil }
+
+	case t.Kind() == reflect.Bool:
+		f := &BoolFlag{Value: field.Bool()}
+		fl, bind = f, func() error { field.SetBool(f.Value); return nil }
+
+	case t.Kind() == reflect.Int && tag.count:
+		f := &CountFlag{Value: int(field.Int())}
+		fl, bind = f, func() error { field.SetInt(int64(f.Value)); return nil }
+
+	case t.Kind() == reflect.Int:
+		f := &IntFlag{Value: int(field.Int())}
+		fl, bind = f, func() error { field.SetInt(int64(f.Value)); return nil }
+
+	case t.Kind() == reflect.Int64:
+		f := &Int64Flag{Value: field.Int()}
+		fl, bind = f, func() error { field.SetInt(f.Value); return nil }
+
+	case t.Kind() == reflect.Uint:
+		f := &UintFlag{Value: uint(field.Uint())}
+		fl, bind = f, func() error { field.SetUint(uint64(f.Value)); return nil }
+
+	case t.ConvertibleTo(stringSliceType):
+		f := &StringSliceFlag{Value: field.Convert(stringSliceType).Interface().([]string)}
+		fl, bind = f, func() error { field.Set(reflect.ValueOf(f.Value).Convert(t)); return nil }
+
+	case t.ConvertibleTo(stringMapType):
+		f := &StringMapFlag{Value: field.Convert(stringMapType).Interface().(map[string]string)}
+		fl, bind = f, func() error { field.Set(reflect.ValueOf(f.Value).Convert(t)); return nil }
+
+	case t.Kind() == reflect.Slice || t.Kind() == reflect.Map:
+		f := &JSONFlag[json.RawMessage]{
+			// check that the json can be decoded into the field
+			Validate: func(value json.RawMessage) error {
+				return json.Unmarshal(value, reflect.New(t).Interface())
+			},
+		}
+		if !field.IsNil() {
+			def, err := json.Marshal(field.Interface())
+			if err != nil {
+				return nil, err
+			}
+			f.Value = def
+		}
+		fl, bind = f, func() error {
+			field.Set(reflect.Zero(t))
+			return json.Unmarshal(f.Value, field.Addr().Interface())
+		}
+
+	default:
+		return nil, fmt.Errorf("unsupported type %s", t)
+	}
+
+	// all built-in flags share these fields
+	fv := reflect.ValueOf(fl).Elem()
+	fv.FieldByName("Name").SetString(tag.name)
+	fv.FieldByName("Alias").SetString(tag.alias)
+	fv.FieldByName("EnvVar").SetString(tag.envVar)
+	fv.FieldByName("Description").SetString(desc)
+
+	return &boundFlag{Flag: fl, bind: bind}, nil
+}
+
+// bindTextField creates a flag for a field that implements encoding.TextUnmarshaler.
+// The value is checked by unmarshalling it into a new value of the field type.
+func bindTextField(field reflect.Value) (*StringFlag, error) {
+	t := field.Type()
+	f := &StringFlag{
+		Validate: func(value string) error {
+			return reflect.New(t).Interface().(encoding.TextUnmarshaler).UnmarshalText([]byte(value))
+		},
+	}
+
+	if m, ok := field.Addr().Interface().(encoding.TextMarshaler); ok && !field.IsZero() {
+		def, err := m.MarshalText()
+		if err != nil {
+			return nil, err
+		}
+		f.Value = string(def)
+	}
+
+	return f, nil
+}
+
+// kebabCase converts a field name to a flag name, eg: DBConn => db-conn, MaxRetries => max-retries
+func kebabCase(name string) string {
+	runes := []rune(name)
+
+	var b strings.Builder
+	for i, r := range runes {
+		if i > 0 && unicode.IsUpper(r) {
+			prev := runes[i-1]
+			nextLower := i+1 < len(runes) && unicode.IsLower(runes[i+1])
+			if unicode.IsLower(prev) || unicode.IsDigit(prev) || (unicode.IsUpper(prev) && nextLower) {
+				b.WriteByte('-')
+			}
+		}
+
+		b.WriteRune(unicode.ToLower(r))
+	}
+
+	return b.String()
+}
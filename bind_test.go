@@ -0,0 +1,326 @@
+package cli
+
+import (
+	"net/netip"
+	"net/url"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestKebabCase(t *testing.T) {
+	tests := []struct {
+		name string
+		want string
+	}{
+		{"Name", "name"},
+		{"DBConn", "db-conn"},
+		{"MaxRetries", "max-retries"},
+		{"HTTPPort", "http-port"},
+		{"ID", "id"},
+		{"Retry2Count", "retry2-count"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := kebabCase(tt.name); got != tt.want {
+				t.Errorf("kebabCase() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+type testBindDB struct {
+	Host string `desc:"Database host"`
+	Port int
+}
+
+type testBindEmbedded struct {
+	Region string
+}
+
+type testBindConfig struct {
+	DBConn  string `cli:"db-conn,alias=d,env=TEST_BIND_DB_CONN,required" desc:"Database connection string"`
+	Timeout time.Duration
+	Tags    []string
+	Labels  map[string]string
+	Weights map[string]float64
+	Verbose int    `cli:"verbose,alias=v,count"`
+	Debug   bool   `cli:",hidden"`
+	Skipped string `cli:"-"`
+	Retries uint
+	Env     string
+	DB      testBindDB
+	testBindEmbedded
+
+	unexported string
+}
+
+func TestBind(t *testing.T) {
+	t.Setenv("TEST_BIND_DB_CONN", "postgres://env")
+
+	cfg := testBindConfig{
+		Env:     "dev",
+		Retries: 3,
+		DB:      testBindDB{Port: 5432},
+	}
+
+	cmd, err := Cmd{
+		Name:   "test",
+		Action: func(args map[string]string) {},
+	}.FlagsFrom(&cfg)
+	if err != nil {
+		t.Fatalf("FlagsFrom() unexpected error = %v", err)
+	}
+
+	if len(cmd.ReqFlags) != 1 || cmd.ReqFlags[0].GetName() != "db-conn" {
+		t.Fatalf("FlagsFrom() ReqFlags = %v, want db-conn", cmd.ReqFlags)
+	}
+
+	var names []string
+	for _, fl := range cmd.OptFlags {
+		names = append(names, fl.GetName())
+	}
+	wantNames := []string{"timeout", "tags", "labels", "weights", "verbose", "debug", "retries", "env", "db-host", "db-port", "region"}
+	if !reflect.DeepEqual(names, wantNames) {
+		t.Errorf("FlagsFrom() OptFlags = %v, want %v", names, wantNames)
+	}
+
+	err = cmd.run([]string{
+		"--timeout=1m30s",
+		"--tags=a, b",
+		"--labels=env=prod,team=core",
+		`--weights={"a":0.5}`,
+		"-vv",
+		"--debug",
+		"--db-host=localhost",
+		"--region=eu",
+	}, []string{"test"})
+	if err != nil {
+		t.Fatalf("run() unexpected error = %v", err)
+	}
+
+	want := testBindConfig{
+		DBConn:           "postgres://env",
+		Timeout:          90 * time.Second,
+		Tags:             []string{"a", "b"},
+		Labels:           map[string]string{"env": "prod", "team": "core"},
+		Weights:          map[string]float64{"a": 0.5},
+		Verbose:          2,
+		Debug:            true,
+		Retries:          3,
+		Env:              "dev",
+		DB:               testBindDB{Host: "localhost", Port: 5432},
+		testBindEmbedded: testBindEmbedded{Region: "eu"},
+	}
+	if !reflect.DeepEqual(cfg, want) {
+		t.Errorf("run() cfg = %+v, want %+v", cfg, want)
+	}
+}
+
+func TestBind_file(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "creds.json")
+	if err := os.WriteFile(path, []byte("secret-creds\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	var cfg struct {
+		Creds string `cli:",file"`
+		User  string
+	}
+	cmd, err := Cmd{
+		Name:   "test",
+		Action: func(args map[string]string) {},
+	}.FlagsFrom(&cfg)
+	if err != nil {
+		t.Fatalf("FlagsFrom() unexpected error = %v", err)
+	}
+
+	if err := cmd.run([]string{"--creds=@" + path, "--user=@handle"}, []string{"test"}); err != nil {
+		t.Fatalf("run() unexpected error = %v", err)
+	}
+
+	if cfg.Creds != "secret-creds" {
+		t.Errorf("Creds = %q, want %q", cfg.Creds, "secret-creds")
+	}
+	if cfg.User != "@handle" {
+		t.Errorf("User = %q, want %q", cfg.User, "@handle")
+	}
+}
+
+func TestBind_errors(t *testing.T) {
+	tests := []struct {
+		name    string
+		ptr     any
+		wantErr string
+	}{
+		{
+			name:    "not a pointer",
+			ptr:     testBindConfig{},
+			wantErr: "expected a pointer to a struct",
+		},
+		{
+			name: "unsupported type",
+			ptr: &struct {
+				Ratio float64
+			}{},
+			wantErr: "field Ratio: unsupported type float64",
+		},
+		{
+			name: "unknown option",
+			ptr: &struct {
+				Name string `cli:"name,requird"`
+			}{},
+			wantErr: "unknown cli tag option 'requird'",
+		},
+		{
+			name: "secret on non-string",
+			ptr: &struct {
+				Port int `cli:",secret"`
+			}{},
+			wantErr: "secret is only supported for string fields",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, _, err := Bind(tt.ptr)
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("Bind() error = %v, want %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestBind_invalidJSON(t *testing.T) {
+	var cfg struct {
+		Weights map[string]float64
+	}
+
+	cmd, err := Cmd{
+		Name:   "test",
+		Action: func(args map[string]string) {},
+	}.FlagsFrom(&cfg)
+	if err != nil {
+		t.Fatalf("FlagsFrom() unexpected error = %v", err)
+	}
+
+	err = cmd.run([]string{`--weights={"a":"heavy"}`}, []string{"test"})
+	if err == nil || !strings.Contains(err.Error(), "weights") {
+		t.Errorf("run() error = %v, want invalid weights", err)
+	}
+}
+
+func TestBind_textTypes(t *testing.T) {
+	start := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+	var cfg struct {
+		Endpoint url.URL
+		Proxy    *url.URL
+		IP       netip.Addr
+		Subnet   netip.Prefix
+		Since    time.Time
+		Until    time.Time
+		Server   struct {
+			Host string
+		}
+	}
+	cfg.Until = start
+
+	cmd, err := Cmd{
+		Name:   "test",
+		Action: func(args map[string]string) {},
+	}.FlagsFrom(&cfg)
+	if err != nil {
+		t.Fatalf("FlagsFrom() unexpected error = %v", err)
+	}
+
+	var names []string
+	for _, fl := range cmd.OptFlags {
+		names = append(names, fl.GetName())
+	}
+	wantNames := []string{"endpoint", "proxy", "ip", "subnet", "since", "until", "server-host"}
+	if !reflect.DeepEqual(names, wantNames) {
+		t.Fatalf("FlagsFrom() OptFlags = %v, want %v", names, wantNames)
+	}
+	if df, _ := flagAs[DefaultValueFlag](cmd.OptFlags[5]); df.GetDefault() != "2024-01-02T03:04:05Z" {
+		t.Errorf("default = %v, want %v", df.GetDefault(), "2024-01-02T03:04:05Z")
+	}
+
+	err = cmd.run([]string{
+		"--endpoint=https://example.com/api",
+		"--proxy=http://proxy:3128",
+		"--ip=10.0.0.1",
+		"--subnet=10.0.0.0/8",
+		"--since=2024-06-01T00:00:00Z",
+	}, []string{"test"})
+	if err != nil {
+		t.Fatalf("run() unexpected error = %v", err)
+	}
+
+	if cfg.Endpoint.String() != "https://example.com/api" {
+		t.Errorf("Endpoint = %v", cfg.Endpoint.String())
+	}
+	if cfg.Proxy == nil || cfg.Proxy.Host != "proxy:3128" {
+		t.Errorf("Proxy = %v", cfg.Proxy)
+	}
+	if cfg.IP != netip.MustParseAddr("10.0.0.1") {
+		t.Errorf("IP = %v", cfg.IP)
+	}
+	if cfg.Subnet != netip.MustParsePrefix("10.0.0.0/8") {
+		t.Errorf("Subnet = %v", cfg.Subnet)
+	}
+	if want := time.Date(2024, 6, 1, 0, 0, 0, 0, time.UTC); !cfg.Since.Equal(want) {
+		t.Errorf("Since = %v, want %v", cfg.Since, want)
+	}
+	if !cfg.Until.Equal(start) {
+		t.Errorf("Until = %v, want the default %v", cfg.Until, start)
+	}
+
+	err = cmd.run([]string{"--since=yesterday"}, []string{"test"})
+	if err == nil || !strings.Contains(err.Error(), "since") {
+		t.Errorf("run() error = %v, want invalid since", err)
+	}
+}
+
+func TestCmd_FlagsFrom_error(t *testing.T) {
+	var cfg struct {
+		Ratio float64
+	}
+
+	_, err := Cmd{Name: "test"}.FlagsFrom(&cfg)
+	if err == nil || !strings.Contains(err.Error(), "unsupported type float64") {
+		t.Errorf("FlagsFrom() error = %v, want unsupported type", err)
+	}
+}
+
+func TestBind_setJSON(t *testing.T) {
+	var cfg struct {
+		Ports []int
+		Raw   map[string]int
+	}
+
+	cmd, err := Cmd{
+		Name:   "test",
+		Action: func(args map[string]string) {},
+	}.FlagsFrom(&cfg)
+	if err != nil {
+		t.Fatalf("FlagsFrom() unexpected error = %v", err)
+	}
+
+	err = cmd.run([]string{"--ports=[1,2]", "--set=ports.1=57", "--set=raw.a=2"}, []string{"test"})
+	if err != nil {
+		t.Fatalf("run() unexpected error = %v", err)
+	}
+	if want := []int{1, 57}; !reflect.DeepEqual(cfg.Ports, want) {
+		t.Errorf("Ports = %v, want %v", cfg.Ports, want)
+	}
+	if want := map[string]int{"a": 2}; !reflect.DeepEqual(cfg.Raw, want) {
+		t.Errorf("Raw = %v, want %v", cfg.Raw, want)
+	}
+
+	err = cmd.run([]string{"--set=raw.a=many"}, []string{"test"})
+	if err == nil || !strings.Contains(err.Error(), "raw") {
+		t.Errorf("run() error = %v, want invalid raw", err)
+	}
+}
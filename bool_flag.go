@@ -1,11 +1,22 @@
 package cli
 
+import (
+	"fmt"
+	"strconv"
+)
+
 // BoolFlag is a flag that if provided, the value will be true.
 // If not provided, the value will be false.
+// An explicit value can also be provided, eg: --flag=false, or via the env var.
+// CLI args take precedence.
+// The flag is only reported as loaded if it is provided, eg: for ReqFlags and flag constraints.
 type BoolFlag struct {
 	Name        string
 	Alias       string
+	EnvVar      string
+	EnvVars     []string // fallback env vars, checked in order if EnvVar is not set
 	Description string
+	Validate    func(value bool) error // see ValidatedFlag
 	Value       bool
 }
 
@@ -17,14 +28,52 @@ func (flag *BoolFlag) GetAlias() string {
 	return flag.Alias
 }
 
+func (flag *BoolFlag) GetEnvVars() []string {
+	return envVarList(flag.EnvVar, flag.EnvVars)
+}
+
 func (flag *BoolFlag) GetDescription() string {
 	desc := flag.Description
 
+	desc = addEnvVarDesc(desc, flag.GetEnvVars())
+
 	return desc
 }
 
+func (flag *BoolFlag) GetDefault() string {
+	return ""
+}
+
+func (flag *BoolFlag) GetPlaceholder() string {
+	return ""
+}
+
 func (flag *BoolFlag) Load(argFound bool, argVal *string) (loaded bool, err error) {
-	flag.Value = argFound
+	if argFound {
+		if argVal == nil {
+			flag.Value = true
+			return true, nil
+		}
+
+		return true, flag.parse(*argVal)
+	}
+
+	flag.Value = false
+
+	// only report the flag as loaded if it was provided, eg: for flag constraints
+	return false, nil
+}
+
+func (flag *BoolFlag) ValidateValue() error {
+	return runValidate(flag.Validate, flag.Value)
+}
+
+func (flag *BoolFlag) parse(val string) error {
+	b, err := strconv.ParseBool(val)
+	if err != nil {
+		return fmt.Errorf("'%s' is not a valid bool", val)
+	}
 
-	return true, nil
+	flag.Value = b
+	return nil
 }
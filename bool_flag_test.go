@@ -0,0 +1,42 @@
+package cli
+
+import "testing"
+
+func TestBoolFlag(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		args    []string
+		env     map[string]string
+		want    bool
+		wantErr bool
+	}{
+		{name: "not provided", want: false},
+		{name: "provided", args: []string{"--debug"}, want: true},
+		{name: "explicit true", args: []string{"--debug=true"}, want: true},
+		{name: "explicit false", args: []string{"--debug=false"}, want: false},
+		{name: "env var", env: map[string]string{"DEBUG": "true"}, want: true},
+		{name: "env var false", env: map[string]string{"DEBUG": "0"}, want: false},
+		{name: "args take precedence", args: []string{"--debug=false"}, env: map[string]string{"DEBUG": "true"}, want: false},
+		{name: "invalid", args: []string{"--debug=maybe"}, wantErr: true},
+		{name: "invalid env var", env: map[string]string{"DEBUG": "maybe"}, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			flag := &BoolFlag{Name: "debug", EnvVar: "DEBUG"}
+			env := tt.env
+			if env == nil {
+				env = map[string]string{}
+			}
+
+			err := runTestCmd(tt.args, env, flag)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("run() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err == nil && flag.Value != tt.want {
+				t.Errorf("Value = %v, want %v", flag.Value, tt.want)
+			}
+		})
+	}
+}
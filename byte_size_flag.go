@@ -0,0 +1,183 @@
+package cli
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// ByteSize is a number of bytes
+type ByteSize uint64
+
+// Common byte sizes
+const (
+	Byte ByteSize = 1
+
+	KB ByteSize = 1000 * Byte
+	MB ByteSize = 1000 * KB
+	GB ByteSize = 1000 * MB
+	TB ByteSize = 1000 * GB
+	PB ByteSize = 1000 * TB
+
+	KiB ByteSize = 1024 * Byte
+	MiB ByteSize = 1024 * KiB
+	GiB ByteSize = 1024 * MiB
+	TiB ByteSize = 1024 * GiB
+	PiB ByteSize = 1024 * TiB
+)
+
+var byteSizeUnits = map[string]ByteSize{
+	"":    Byte,
+	"b":   Byte,
+	"k":   KB,
+	"kb":  KB,
+	"m":   MB,
+	"mb":  MB,
+	"g":   GB,
+	"gb":  GB,
+	"t":   TB,
+	"tb":  TB,
+	"p":   PB,
+	"pb":  PB,
+	"ki":  KiB,
+	"kib": KiB,
+	"mi":  MiB,
+	"mib": MiB,
+	"gi":  GiB,
+	"gib": GiB,
+	"ti":  TiB,
+	"tib": TiB,
+	"pi":  PiB,
+	"pib": PiB,
+}
+
+// ParseByteSize parses a human readable byte size, eg: "512MiB", "1.5GB" or "1024".
+// Decimal units (KB, MB, ...) are powers of 1000, binary units (KiB, MiB, ...) are powers of 1024.
+// Units are case-insensitive and a number without a unit is a number of bytes.
+func ParseByteSize(s string) (ByteSize, error) {
+	trimmed := strings.TrimSpace(s)
+
+	// split the number from the unit
+	i := strings.IndexFunc(trimmed, func(r rune) bool {
+		return !unicode.IsDigit(r) && r != '.'
+	})
+	if i == -1 {
+		i = len(trimmed)
+	}
+	num, unit := trimmed[:i], strings.ToLower(strings.TrimSpace(trimmed[i:]))
+
+	multiplier, ok := byteSizeUnits[unit]
+	if !ok || num == "" {
+		return 0, fmt.Errorf("'%s' is not a valid byte size", s)
+	}
+
+	// whole numbers are parsed as ints, to avoid losing precision
+	if n, err := strconv.ParseUint(num, 10, 64); err == nil {
+		if n > math.MaxUint64/uint64(multiplier) {
+			return 0, fmt.Errorf("'%s' is too large", s)
+		}
+
+		return ByteSize(n) * multiplier, nil
+	}
+
+	f, err := strconv.ParseFloat(num, 64)
+	if err != nil {
+		return 0, fmt.Errorf("'%s' is not a valid byte size", s)
+	}
+
+	size := f * float64(multiplier)
+	if size >= math.MaxUint64 {
+		return 0, fmt.Errorf("'%s' is too large", s)
+	}
+
+	return ByteSize(size), nil
+}
+
+// String formats the size using the largest binary unit that results in a whole number, eg: "512MiB"
+func (b ByteSize) String() string {
+	units := []struct {
+		size ByteSize
+		name string
+	}{
+		{PiB, "PiB"},
+		{TiB, "TiB"},
+		{GiB, "GiB"},
+		{MiB, "MiB"},
+		{KiB, "KiB"},
+	}
+
+	for _, u := range units {
+		if b >= u.size && b%u.size == 0 {
+			return fmt.Sprintf("%d%s", b/u.size, u.name)
+		}
+	}
+
+	return fmt.Sprintf("%dB", uint64(b))
+}
+
+// ByteSizeFlag is a flag for sizes, eg: memory limits.
+// Values such as "512MiB", "1.5GB" or "1024" (bytes) are accepted, see ParseByteSize.
+// The value can be provided by cli args or env var.
+// CLI args take precedence.
+type ByteSizeFlag struct {
+	Name        string
+	Alias       string
+	EnvVar      string
+	EnvVars     []string // fallback env vars, checked in order if EnvVar is not set
+	Description string
+	Validate    func(value ByteSize) error // see ValidatedFlag
+	Value       ByteSize                   // can provide a default value here
+}
+
+func (flag *ByteSizeFlag) GetName() string {
+	return flag.Name
+}
+
+func (flag *ByteSizeFlag) GetAlias() string {
+	return flag.Alias
+}
+
+func (flag *ByteSizeFlag) GetEnvVars() []string {
+	return envVarList(flag.EnvVar, flag.EnvVars)
+}
+
+func (flag *ByteSizeFlag) GetDescription() string {
+	desc := flag.Description
+
+	desc = addEnvVarDesc(desc, flag.GetEnvVars())
+
+	return desc
+}
+
+func (flag *ByteSizeFlag) GetDefault() string {
+	if flag.Value == 0 {
+		return ""
+	}
+
+	return flag.Value.String()
+}
+
+func (flag *ByteSizeFlag) GetPlaceholder() string {
+	return "size"
+}
+
+func (flag *ByteSizeFlag) Load(argFound bool, argVal *string) (loaded bool, err error) {
+	val, found, err := flagValue(flag.Name, argFound, argVal)
+	if !found || err != nil {
+		return found, err
+	}
+
+	size, err := ParseByteSize(val)
+	if err != nil {
+		return false, err
+	}
+
+	flag.Value = size
+	return true, nil
+}
+
+func (flag *ByteSizeFlag) ValidateValue() error {
+	return runValidate(flag.Validate, flag.Value)
+}
@@ -0,0 +1,57 @@
+package cli
+
+import (
+	"testing"
+)
+
+func TestParseByteSize(t *testing.T) {
+	tests := []struct {
+		in      string
+		want    ByteSize
+		wantErr bool
+	}{
+		{in: "1024", want: 1024},
+		{in: "512MiB", want: 512 * MiB},
+		{in: "1.5GB", want: 1500 * MB},
+		{in: "1.5 gib", want: 1536 * MiB},
+		{in: "10k", want: 10 * KB},
+		{in: "0", want: 0},
+		{in: "", wantErr: true},
+		{in: "MiB", wantErr: true},
+		{in: "-5MiB", wantErr: true},
+		{in: "5 lightyears", wantErr: true},
+		{in: "100000000PiB", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.in, func(t *testing.T) {
+			got, err := ParseByteSize(tt.in)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("ParseByteSize() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if got != tt.want {
+				t.Errorf("ParseByteSize() got = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestByteSize_String(t *testing.T) {
+	tests := []struct {
+		in   ByteSize
+		want string
+	}{
+		{in: 0, want: "0B"},
+		{in: 1000, want: "1000B"},
+		{in: 512 * MiB, want: "512MiB"},
+		{in: 1536 * MiB, want: "1536MiB"},
+		{in: 2 * GiB, want: "2GiB"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.want, func(t *testing.T) {
+			if got := tt.in.String(); got != tt.want {
+				t.Errorf("String() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
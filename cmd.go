@@ -1,7 +1,9 @@
 package cli
 
 import (
+	"errors"
 	"fmt"
+	"os"
 	"strings"
 )
 
@@ -16,8 +18,16 @@ type Cmd struct {
 	OptFlags    []Flag   // optional flags: if not provided, the default value will be used. Note that the help flag is automatically added to this list.
 	Args        []string // positional args: used to populate the args map passed to the action function.
 	Action      func(args map[string]string)
+	// ActionCtx is used instead of Action, if specified. The context reports where each flag value was loaded from.
+	// Secret values are redacted from the returned error, see Secret.
+	ActionCtx func(ctx *Context) error
 
-	fullPath []string // for internal use only, to keep track of the full path to the command
+	FlagConstraints []FlagConstraint // constraints between flags, eg: MutuallyExclusive(fileFlag, urlFlag)
+
+	fullPath []string          // for internal use only, to keep track of the full path to the command
+	namePath []string          // for internal use only, names of the sub commands leading to this command
+	defaults map[string]string // for internal use only, default values of flags before they are loaded, by flagKey
+	app      *App              // for internal use only, app level options (nil if not run by an App)
 }
 
 func (cmd *Cmd) run(args []string, cmdPath []string) error {
@@ -29,13 +39,16 @@ func (cmd *Cmd) run(args []string, cmdPath []string) error {
 
 		for _, subCmd := range cmd.SubCmds {
 			if subCmd.matchName(subCmdName) {
+				subCmd.app = cmd.app
+				subCmd.namePath = append(append([]string{}, cmd.namePath...), subCmd.Name)
+
 				return subCmd.run(args[1:], append(cmdPath, subCmdName))
 			}
 		}
 	}
 
 	// this command has no action, print help
-	if cmd.Action == nil {
+	if cmd.Action == nil && cmd.ActionCtx == nil {
 		cmd.printHelp()
 		return nil
 	}
@@ -48,49 +61,85 @@ func (cmd *Cmd) run(args []string, cmdPath []string) error {
 		}
 	}
 
+	// record defaults for the help text, before flags are loaded
+	cmd.defaults = flagDefaults(cmd.ReqFlags, cmd.OptFlags)
+
 	// extract flags from args
 	var flagArgs []string
 	args, flagArgs = splitFlagArgs(args)
 
+	// print config instead of running the action, if requested
+	printConfig, err := isPrintConfig(flagArgs)
+	if err != nil {
+		return err
+	}
+
 	// load required flags
 	// if any required flags are not provided, print help and exit
 	var flagErrs []error
-	for _, fl := range cmd.ReqFlags {
-		found, val, err := LoadFlagFromArgs(fl.GetName(), fl.GetAlias(), flagArgs)
-		if err != nil {
-			flagErrs = append(flagErrs, fmt.Errorf("load flag from args: '%s': %w", fl.GetName(), err))
-			continue
-		}
+	loader, err := cmd.newFlagLoader(flagArgs)
+	if err != nil {
+		return err
+	}
 
-		loaded, err := fl.Load(found, val)
+	ctx := &Context{provenances: make(map[string]Provenance)}
+
+	// loaded flags by flagKey, only needed to validate constraints
+	var loadedFlags map[string]bool
+	if len(cmd.FlagConstraints) > 0 {
+		loadedFlags = make(map[string]bool)
+	}
+	for _, fl := range cmd.ReqFlags {
+		loaded, prov, err := loader.load(fl)
 		if err != nil {
-			flagErrs = append(flagErrs, fmt.Errorf("loading flag: '%s': %w", fl.GetName(), err))
+			flagErrs = append(flagErrs, err)
 			continue
 		}
 
 		if !loaded {
 			flagErrs = append(flagErrs, fmt.Errorf("flag: '%s' not provided", fl.GetName()))
 		}
+		if loadedFlags != nil {
+			loadedFlags[flagKey(fl)] = loaded
+		}
+		ctx.provenances[flagKey(fl)] = prov
 	}
 
 	// load optional flags
 	for _, fl := range cmd.OptFlags {
-		found, val, err := LoadFlagFromArgs(fl.GetName(), fl.GetAlias(), flagArgs)
+		loaded, prov, err := loader.load(fl)
 		if err != nil {
-			flagErrs = append(flagErrs, fmt.Errorf("load flag from args: '%s': %w", fl.GetName(), err))
+			flagErrs = append(flagErrs, err)
 			continue
 		}
+		if loadedFlags != nil {
+			loadedFlags[flagKey(fl)] = loaded
+		}
+		ctx.provenances[flagKey(fl)] = prov
+	}
 
-		_, err = fl.Load(found, val)
-		if err != nil {
-			flagErrs = append(flagErrs, fmt.Errorf("loading flag: '%s': %w", fl.GetName(), err))
-			continue
+	// every --set override must match a flag
+	if err := checkSetOverrides(loader.sets, cmd.ReqFlags, cmd.OptFlags); err != nil {
+		flagErrs = append(flagErrs, err)
+	}
+
+	// validate constraints between flags
+	for _, c := range cmd.FlagConstraints {
+		if err := c.check(loadedFlags); err != nil {
+			flagErrs = append(flagErrs, err)
 		}
 	}
 
+	if printConfig {
+		allFlags := append(append([]Flag{}, cmd.ReqFlags...), cmd.OptFlags...)
+		printProvenance(os.Stdout, allFlags, ctx, flagSecrets(cmd.ReqFlags, cmd.OptFlags))
+	}
+
 	// print help and exit if any errors were encountered loading flags
 	if len(flagErrs) > 0 {
-		cmd.printHelp()
+		if !printConfig {
+			cmd.printHelp()
+		}
 
 		flagErrStr := "cmd flag error: "
 		if len(flagErrs) > 1 {
@@ -104,7 +153,14 @@ func (cmd *Cmd) run(args []string, cmdPath []string) error {
 			flagErrStr += e.Error()
 		}
 
-		return fmt.Errorf(flagErrStr)
+		// never leak secret values in errors
+		flagErrStr = redact(flagErrStr, flagSecrets(cmd.ReqFlags, cmd.OptFlags))
+
+		return errors.New(flagErrStr)
+	}
+
+	if printConfig {
+		return nil
 	}
 
 	// map out positional args
@@ -116,8 +172,13 @@ func (cmd *Cmd) run(args []string, cmdPath []string) error {
 
 		argsMap[argId] = args[i]
 	}
+	ctx.Args = argsMap
 
 	// run action
+	if cmd.ActionCtx != nil {
+		// never leak secret values in errors
+		return redactErr(cmd.ActionCtx(ctx), flagSecrets(cmd.ReqFlags, cmd.OptFlags))
+	}
 	cmd.Action(argsMap)
 
 	return nil
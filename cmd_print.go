@@ -9,7 +9,7 @@ import (
 
 const descriptionWrapLimit = 50
 
-var helpFlag = StringFlag{
+var helpFlag = BoolFlag{
 	Name:        "help",
 	Alias:       "h",
 	Description: `Print documentation for command`,
@@ -38,9 +38,42 @@ func (cmd *Cmd) printHelp() {
 
 	printCommandsSection("Commands:", cmd.SubCmds)
 
-	printFlagsSection("Required Flags:", cmd.ReqFlags)
+	reqFlags, optFlags := cmd.autoEnvFlags(cmd.ReqFlags), cmd.autoEnvFlags(cmd.OptFlags)
 
-	printFlagsSection("Optional Flags:", append(cmd.OptFlags, &helpFlag))
+	printFlagsSection("Required Flags:", visibleFlags(reqFlags), cmd.defaults)
+
+	printFlagsSection("Optional Flags:", append(visibleFlags(optFlags), cmd.builtinFlags()...), cmd.defaults)
+
+	printFlagsSection("Deprecated Flags:", deprecatedFlags(reqFlags, optFlags), cmd.defaults)
+
+	printConstraintsSection("Flag Constraints:", cmd.FlagConstraints)
+}
+
+// builtinFlags returns the flags that are accepted by every command
+func (cmd *Cmd) builtinFlags() []Flag {
+	flags := []Flag{&helpFlag, &printConfigFlag}
+
+	if hasSetOverrideFlags(cmd.ReqFlags, cmd.OptFlags) {
+		flags = append(flags, &setFlag)
+	}
+
+	if cmd.app != nil && cmd.app.Profiles {
+		flags = append(flags, cmd.app.profileFlag())
+	}
+
+	return flags
+}
+
+func printConstraintsSection(title string, constraints []FlagConstraint) {
+	if len(constraints) == 0 {
+		return
+	}
+
+	fmt.Println(title)
+	for _, c := range constraints {
+		fmt.Println("    " + c.String())
+	}
+	fmt.Println()
 }
 
 func printCommandsSection(title string, cmds []Cmd) {
@@ -76,7 +109,108 @@ func printCommandsSection(title string, cmds []Cmd) {
 	fmt.Println()
 }
 
-func printFlagsSection(title string, flags []Flag) {
+// visibleFlags returns the flags that are neither hidden nor deprecated
+func visibleFlags(flags []Flag) []Flag {
+	var visible []Flag
+	for _, fl := range flags {
+		if !isHidden(fl) && !isDeprecated(fl) {
+			visible = append(visible, fl)
+		}
+	}
+
+	return visible
+}
+
+// deprecatedFlags returns the flags that are deprecated, but not hidden.
+// The description of each flag is extended with the deprecation warning.
+func deprecatedFlags(flags ...[]Flag) []Flag {
+	var deprecated []Flag
+	for _, fls := range flags {
+		for _, fl := range fls {
+			if isDeprecated(fl) && !isHidden(fl) {
+				deprecated = append(deprecated, &describedFlag{
+					Flag:        fl,
+					description: addDescLine(fl.GetDescription(), "> deprecated"+deprecationDetails(fl)),
+				})
+			}
+		}
+	}
+
+	return deprecated
+}
+
+// describedFlag overrides the description of a flag, for documentation only
+type describedFlag struct {
+	Flag
+	description string
+}
+
+func (flag *describedFlag) GetDescription() string {
+	return flag.description
+}
+
+// Unwrap returns the wrapped flag
+func (flag *describedFlag) Unwrap() Flag {
+	return flag.Flag
+}
+
+// flagDefaults returns the formatted default values of flags that implement DefaultValueFlag
+func flagDefaults(flags ...[]Flag) map[string]string {
+	defaults := make(map[string]string)
+	for _, fls := range flags {
+		for _, fl := range fls {
+			if df, ok := flagAs[DefaultValueFlag](fl); ok {
+				defaults[flagKey(fl)] = df.GetDefault()
+			}
+		}
+	}
+
+	return defaults
+}
+
+// flagDefault returns the default value of a flag from defaults, falling back to the current default
+func flagDefault(fl Flag, defaults map[string]string) string {
+	if def, ok := defaults[flagKey(fl)]; ok {
+		return def
+	}
+
+	if df, ok := flagAs[DefaultValueFlag](fl); ok {
+		return df.GetDefault()
+	}
+
+	return ""
+}
+
+// flagUsageName formats the name of a flag for the help text, including a value placeholder, eg: --count=<int>
+func flagUsageName(fl Flag) string {
+	name := formatFlag(fl.GetName())
+
+	if df, ok := flagAs[DefaultValueFlag](fl); ok && df.GetPlaceholder() != "" {
+		name += "=<" + df.GetPlaceholder() + ">"
+	}
+
+	return name
+}
+
+// flagUsageDescription appends the default value, if any, to the first line of the flag description
+func flagUsageDescription(fl Flag, defaults map[string]string) string {
+	desc := fl.GetDescription()
+
+	def := flagDefault(fl, defaults)
+	if def == "" {
+		return desc
+	}
+
+	first, rest, multiline := strings.Cut(desc, "\n")
+	first = strings.TrimSpace(first + " (default: " + def + ")")
+	if multiline {
+		return first + "\n" + rest
+	}
+
+	return first
+}
+
+func printFlagsSection(title string, flags []Flag, defaults map[string]string) {
 	if len(flags) == 0 {
 		return
 	}
@@ -91,9 +225,9 @@ func printFlagsSection(title string, flags []Flag) {
 			alias = formatAlias(flag.GetAlias())
 		}
 
-		name := formatFlag(flag.GetName())
+		name := flagUsageName(flag)
 
-		descLines := wrapText(flag.GetDescription(), descriptionWrapLimit)
+		descLines := wrapText(flagUsageDescription(flag, defaults), descriptionWrapLimit)
 
 		for i := range descLines {
 			if i == 0 {
@@ -0,0 +1,100 @@
+package cli
+
+import (
+	"testing"
+)
+
+func Test_flagUsage(t *testing.T) {
+	tests := []struct {
+		name     string
+		flag     Flag
+		wantName string
+		wantDesc string
+	}{
+		{
+			name:     "int with default",
+			flag:     &IntFlag{Name: "count", Description: "Number of items", Value: 5},
+			wantName: "--count=<int>",
+			wantDesc: "Number of items (default: 5)",
+		},
+		{
+			name:     "zero default",
+			flag:     &IntFlag{Name: "count", Description: "Number of items"},
+			wantName: "--count=<int>",
+			wantDesc: "Number of items",
+		},
+		{
+			name:     "multiline description",
+			flag:     &StringFlag{Name: "env", EnvVar: "ENV", Description: "Environment", Value: "dev"},
+			wantName: "--env=<string>",
+			wantDesc: "Environment (default: dev)\n> env var: ENV",
+		},
+		{
+			name:     "secret",
+			flag:     &SecretFlag{Name: "token", Value: "hunter2"},
+			wantName: "--token=<secret>",
+			wantDesc: "",
+		},
+		{
+			name:     "secret json",
+			flag:     &JSONFlag[map[string]string]{Name: "creds", Secret: true, Value: map[string]string{"key": "hunter2"}},
+			wantName: "--creds=<json>",
+			wantDesc: "> schema: {string: string}",
+		},
+		{
+			name:     "json schema",
+			flag:     &JSONFlag[testSetServer]{Name: "server", Description: "Server config"},
+			wantName: "--server=<json>",
+			wantDesc: "Server config\n> schema: {\"host\": string, \"port\": int}",
+		},
+		{
+			name:     "bool",
+			flag:     &BoolFlag{Name: "verbose", Description: "Verbose output"},
+			wantName: "--verbose",
+			wantDesc: "Verbose output",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := flagUsageName(tt.flag); got != tt.wantName {
+				t.Errorf("flagUsageName() = %q, want %q", got, tt.wantName)
+			}
+			if got := flagUsageDescription(tt.flag, nil); got != tt.wantDesc {
+				t.Errorf("flagUsageDescription() = %q, want %q", got, tt.wantDesc)
+			}
+		})
+	}
+}
+
+// uncomparableFlag can't be used as a map key, since its dynamic type holds a slice
+type uncomparableFlag struct {
+	*IntFlag
+	aliases []string
+}
+
+func Test_flagDefault(t *testing.T) {
+	countFlag := &IntFlag{Name: "count", Value: 5}
+	uncomparable := uncomparableFlag{IntFlag: &IntFlag{Name: "limit", Value: 10}}
+	defaults := flagDefaults([]Flag{countFlag, Hide(uncomparable)})
+
+	// the value after loading is not the default
+	countFlag.Value = 3
+
+	tests := []struct {
+		name string
+		flag Flag
+		want string
+	}{
+		{name: "recorded before loading", flag: countFlag, want: "5"},
+		{name: "wrapped", flag: Hide(countFlag), want: "5"},
+		{name: "uncomparable", flag: uncomparable, want: "10"},
+		{name: "not recorded", flag: &IntFlag{Name: "other", Value: 1}, want: "1"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := flagDefault(tt.flag, defaults); got != tt.want {
+				t.Errorf("flagDefault() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,251 @@
+package cli
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+)
+
+// configFile holds the values of a JSON config file.
+// Flags are looked up by name, nested under the names of the commands leading to them, eg:
+//
+//	{
+//	    "db-conn": "postgres://localhost",  // any command with a --db-conn flag
+//	    "db": {
+//	        "migrate": {
+//	            "steps": 3                  // db migrate --steps
+//	        }
+//	    }
+//	}
+//
+// Line comments (//) and trailing commas are allowed, eg: in the template printed by App.ConfigTemplateCmd.
+// The most specific key is used: db.migrate.steps takes precedence over db.steps, which takes precedence over steps.
+type configFile struct {
+	path   string
+	source Source // SourceConfig or SourceProfile
+	values map[string]any
+}
+
+// UserConfigPath returns the default path of a config file for the app: <user config dir>/<app>/config.json,
+// eg: $XDG_CONFIG_HOME/myapp/config.json on linux.
+func UserConfigPath(app string) string {
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return ""
+	}
+
+	return filepath.Join(dir, app, "config.json")
+}
+
+// userConfigDir is like os.UserConfigDir, but reads env vars with lookupEnv
+func userConfigDir(lookupEnv func(name string) (string, bool)) (string, error) {
+	switch runtime.GOOS {
+	case "windows":
+		if dir, _ := lookupEnv("AppData"); dir != "" {
+			return dir, nil
+		}
+		return "", errors.New("%AppData% is not defined")
+	case "darwin", "ios":
+		home, err := userHomeDir(lookupEnv)
+		if err != nil {
+			return "", err
+		}
+		return filepath.Join(home, "Library", "Application Support"), nil
+	case "plan9":
+		home, err := userHomeDir(lookupEnv)
+		if err != nil {
+			return "", err
+		}
+		return filepath.Join(home, "lib"), nil
+	}
+
+	if dir, _ := lookupEnv("XDG_CONFIG_HOME"); dir != "" {
+		if !filepath.IsAbs(dir) {
+			return "", errors.New("path in $XDG_CONFIG_HOME is relative")
+		}
+		return dir, nil
+	}
+
+	home, err := userHomeDir(lookupEnv)
+	if err != nil {
+		return "", err
+	}
+
+	return filepath.Join(home, ".config"), nil
+}
+
+// loadConfigFile loads the config file at path, or the first of searchPaths that exists.
+// An explicit path must exist. If no path is provided and none of the search paths exist, nil is returned.
+func loadConfigFile(path string, searchPaths []string, lookupEnv func(name string) (string, bool)) (*configFile, error) {
+	if path != "" {
+		return readConfigFile(path, lookupEnv)
+	}
+
+	for _, p := range searchPaths {
+		if p == "" {
+			continue
+		}
+
+		cfg, err := readConfigFile(p, lookupEnv)
+		if errors.Is(err, fs.ErrNotExist) {
+			continue
+		}
+
+		return cfg, err
+	}
+
+	return nil, nil
+}
+
+func readConfigFile(path string, lookupEnv func(name string) (string, bool)) (*configFile, error) {
+	path, err := expandPath(path, lookupEnv)
+	if err != nil {
+		return nil, fmt.Errorf("config file '%s': %w", path, err)
+	}
+
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("config file: %w", err)
+	}
+
+	dec := json.NewDecoder(bytes.NewReader(stripJSONComments(b)))
+	dec.UseNumber()
+
+	var values map[string]any
+	if err := dec.Decode(&values); err != nil {
+		return nil, fmt.Errorf("config file '%s': invalid json: %w", path, err)
+	}
+
+	return &configFile{path: path, source: SourceConfig, values: values}, nil
+}
+
+// lookup finds the value of a flag for the command path.
+// key is the dot separated path to the value in the file, eg: db.migrate.steps
+func (cfg *configFile) lookup(cmdPath []string, name string) (key string, val string, found bool, err error) {
+	if cfg == nil || name == "" {
+		return "", "", false, nil
+	}
+
+	for i := len(cmdPath); i >= 0; i-- {
+		keys := append(append([]string{}, cmdPath[:i]...), name)
+
+		raw, ok := cfg.find(keys)
+		if !ok {
+			continue
+		}
+
+		key = strings.Join(keys, ".")
+		val, err = configValueString(raw)
+		if err != nil {
+			return key, "", false, fmt.Errorf("%s file '%s', key '%s': %w", cfg.source, cfg.path, key, err)
+		}
+
+		return key, val, true, nil
+	}
+
+	return "", "", false, nil
+}
+
+func (cfg *configFile) find(keys []string) (any, bool) {
+	var cur any = cfg.values
+	for _, k := range keys {
+		m, ok := cur.(map[string]any)
+		if !ok {
+			return nil, false
+		}
+
+		cur, ok = m[k]
+		if !ok {
+			return nil, false
+		}
+	}
+
+	return cur, cur != nil
+}
+
+// configValueString converts a json value to the string representation used by flags.
+// Objects and arrays are returned as json, eg: for JSONFlag.
+func configValueString(v any) (string, error) {
+	switch val := v.(type) {
+	case string:
+		return val, nil
+	case json.Number:
+		return val.String(), nil
+	case bool:
+		if val {
+			return "true", nil
+		}
+		return "false", nil
+	default:
+		b, err := json.Marshal(val)
+		if err != nil {
+			return "", err
+		}
+		return string(b), nil
+	}
+}
+
+// stripJSONComments removes line comments (//) and trailing commas from json
+func stripJSONComments(b []byte) []byte {
+	out := make([]byte, 0, len(b))
+	inString := false
+
+	for i := 0; i < len(b); i++ {
+		c := b[i]
+
+		if inString {
+			out = append(out, c)
+			if c == '\\' && i+1 < len(b) {
+				i++
+				out = append(out, b[i])
+			} else if c == '"' {
+				inString = false
+			}
+			continue
+		}
+
+		switch {
+		case c == '"':
+			inString = true
+			out = append(out, c)
+
+		case c == '/' && i+1 < len(b) && b[i+1] == '/':
+			for i < len(b) && b[i] != '\n' {
+				i++
+			}
+			i-- // keep the newline
+
+		case c == ',' && isTrailingComma(b[i+1:]):
+			// skip
+
+		default:
+			out = append(out, c)
+		}
+	}
+
+	return out
+}
+
+// isTrailingComma returns true if the rest of the json (after a comma) closes an object or array
+func isTrailingComma(rest []byte) bool {
+	for i := 0; i < len(rest); i++ {
+		switch c := rest[i]; {
+		case c == ' ' || c == '\t' || c == '\n' || c == '\r':
+			continue
+		case c == '/' && i+1 < len(rest) && rest[i+1] == '/':
+			for i < len(rest) && rest[i] != '\n' {
+				i++
+			}
+		default:
+			return c == '}' || c == ']'
+		}
+	}
+
+	return false
+}
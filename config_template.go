@@ -0,0 +1,178 @@
+package cli
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+)
+
+// configTemplateCmdName is the name of the command added by App.ConfigTemplateCmd
+const configTemplateCmdName = "config-template"
+
+// configTemplateCmd prints a config file template for all the flags of the app
+func configTemplateCmd(app *App) Cmd {
+	return Cmd{
+		Name:        configTemplateCmdName,
+		Description: "Print a config file template, listing the flags of every command",
+		Action: func(args map[string]string) {
+			writeConfigTemplate(os.Stdout, app)
+		},
+	}
+}
+
+// writeConfigTemplate writes a config file template for the app.
+// Every flag is listed (commented out) with its description, env var, type and default,
+// nested under the names of the commands leading to it. The template can be read as a config file,
+// since comments and trailing commas are ignored.
+func writeConfigTemplate(w io.Writer, app *App) {
+	root := Cmd{
+		Name:     app.Name,
+		SubCmds:  app.SubCmds,
+		ReqFlags: app.ReqFlags,
+		OptFlags: app.OptFlags,
+		app:      app,
+	}
+
+	fmt.Fprintf(w, "// Config file for %s, generated by: %s %s\n", app.Name, app.Name, configTemplateCmdName)
+	fmt.Fprintln(w, "{")
+	writeConfigTemplateCmd(w, &root, 1)
+	fmt.Fprintln(w, "}")
+}
+
+func writeConfigTemplateCmd(w io.Writer, cmd *Cmd, depth int) {
+	indent := strings.Repeat("    ", depth)
+
+	first := true
+	writeFlags := func(flags []Flag, required bool) {
+		for _, fl := range flags {
+			if fl.GetName() == "" || isHidden(fl) || isDeprecated(fl) {
+				continue
+			}
+
+			if !first {
+				fmt.Fprintln(w)
+			}
+			first = false
+
+			for _, line := range strings.Split(fl.GetDescription(), "\n") {
+				if line != "" {
+					fmt.Fprintf(w, "%s// %s\n", indent, line)
+				}
+			}
+
+			fmt.Fprintf(w, "%s// %s\n", indent, configTemplateDetails(fl, required))
+			fmt.Fprintf(w, "%s// %q: %s,\n", indent, fl.GetName(), configTemplateValue(fl))
+		}
+	}
+	writeFlags(cmd.autoEnvFlags(cmd.ReqFlags), true)
+	writeFlags(cmd.autoEnvFlags(cmd.OptFlags), false)
+
+	var subCmds []*Cmd
+	for i := range cmd.SubCmds {
+		subCmd := cmd.SubCmds[i]
+		if subCmd.Name == configTemplateCmdName {
+			continue
+		}
+
+		subCmd.app = cmd.app
+		subCmd.namePath = append(append([]string{}, cmd.namePath...), subCmd.Name)
+		if hasConfigFlags(&subCmd) {
+			subCmds = append(subCmds, &subCmd)
+		}
+	}
+
+	for i, subCmd := range subCmds {
+		if !first {
+			fmt.Fprintln(w)
+		}
+		first = false
+
+		fmt.Fprintf(w, "%s%q: {\n", indent, subCmd.Name)
+		writeConfigTemplateCmd(w, subCmd, depth+1)
+
+		comma := ","
+		if i == len(subCmds)-1 {
+			comma = ""
+		}
+		fmt.Fprintf(w, "%s}%s\n", indent, comma)
+	}
+}
+
+// hasConfigFlags returns true if the command or any of its sub commands has flags to list in a config template
+func hasConfigFlags(cmd *Cmd) bool {
+	for _, fl := range append(append([]Flag{}, cmd.ReqFlags...), cmd.OptFlags...) {
+		if fl.GetName() != "" && !isHidden(fl) && !isDeprecated(fl) {
+			return true
+		}
+	}
+
+	for i := range cmd.SubCmds {
+		if hasConfigFlags(&cmd.SubCmds[i]) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// configTemplateDetails describes the type and default of a flag, eg: "type: int, default: 5"
+func configTemplateDetails(fl Flag, required bool) string {
+	details := []string{"type: " + configTemplateType(fl)}
+
+	if df, ok := flagAs[DefaultValueFlag](fl); ok && df.GetDefault() != "" {
+		details = append(details, "default: "+df.GetDefault())
+	}
+
+	if required {
+		details = append(details, "required")
+	}
+
+	return strings.Join(details, ", ")
+}
+
+func configTemplateType(fl Flag) string {
+	if _, ok := flagAs[*BoolFlag](fl); ok {
+		return "bool"
+	}
+
+	if _, ok := flagAs[*CountFlag](fl); ok {
+		return "int"
+	}
+
+	if df, ok := flagAs[DefaultValueFlag](fl); ok && df.GetPlaceholder() != "" {
+		return df.GetPlaceholder()
+	}
+
+	return "string"
+}
+
+// configTemplateValue returns the default value of a flag as json, or an empty value of its type
+func configTemplateValue(fl Flag) string {
+	var def string
+	if df, ok := flagAs[DefaultValueFlag](fl); ok {
+		def = df.GetDefault()
+	}
+
+	switch configTemplateType(fl) {
+	case "bool":
+		if def == "" {
+			return "false"
+		}
+		return def
+	case "int", "int64", "uint":
+		if def == "" {
+			return "0"
+		}
+		return def
+	case "json":
+		if def == "" || !json.Valid([]byte(def)) {
+			return "null"
+		}
+		return def
+	}
+
+	b, _ := json.Marshal(def)
+	return string(b)
+}
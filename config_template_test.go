@@ -0,0 +1,130 @@
+package cli
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"testing"
+)
+
+func TestWriteConfigTemplate(t *testing.T) {
+	app := &App{
+		Name:     "myapp",
+		OptFlags: []Flag{&BoolFlag{Name: "debug"}, Hide(&StringFlag{Name: "internal"})},
+		SubCmds: []Cmd{
+			{
+				Name:     "db",
+				ReqFlags: []Flag{&StringFlag{Name: "db-conn", EnvVar: "DB_CONN", Description: "Database connection string"}},
+				SubCmds: []Cmd{{
+					Name:     "migrate",
+					OptFlags: []Flag{&IntFlag{Name: "steps", Value: 5}},
+				}},
+			},
+			{
+				Name: "version",
+			},
+		},
+		ConfigTemplateCmd: true,
+	}
+
+	var buf bytes.Buffer
+	writeConfigTemplate(&buf, app)
+	got := buf.String()
+
+	for _, want := range []string{
+		`    // "debug": false,`,
+		`        // Database connection string`,
+		`        // > env var: DB_CONN`,
+		`        // type: string, required`,
+		`        // "db-conn": "",`,
+		`            // type: int, default: 5`,
+		`            // "steps": 5,`,
+	} {
+		if !strings.Contains(got, want+"\n") {
+			t.Errorf("writeConfigTemplate() missing line %q, got:\n%s", want, got)
+		}
+	}
+
+	for _, unwanted := range []string{"internal", "version", configTemplateCmdName + `"`} {
+		if strings.Contains(got, unwanted) {
+			t.Errorf("writeConfigTemplate() contains %q, got:\n%s", unwanted, got)
+		}
+	}
+
+	// the template, with every flag uncommented, is a valid config file
+	uncommented := regexp.MustCompile(`(?m)^(\s*)// (".*)$`).ReplaceAllString(got, "$1$2")
+
+	path := filepath.Join(t.TempDir(), "config.json")
+	if err := os.WriteFile(path, []byte(uncommented), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	cfg, err := readConfigFile(path, os.LookupEnv)
+	if err != nil {
+		t.Fatalf("readConfigFile() error = %v, template:\n%s", err, uncommented)
+	}
+
+	_, val, found, err := cfg.lookup([]string{"db", "migrate"}, "steps")
+	if err != nil || !found || val != "5" {
+		t.Errorf("lookup() = %v, %v, %v, want 5", val, found, err)
+	}
+}
+
+func TestWriteConfigTemplate_autoEnv(t *testing.T) {
+	stepsFlag := &IntFlag{Name: "steps"}
+	app := &App{
+		Name:           "myapp",
+		EnvPrefix:      "MYAPP",
+		AutoEnv:        true,
+		AutoEnvCmdPath: true,
+		SubCmds:        []Cmd{{Name: "migrate", OptFlags: []Flag{stepsFlag}}},
+	}
+
+	var buf bytes.Buffer
+	writeConfigTemplate(&buf, app)
+
+	if want := "        // > env var: MYAPP_MIGRATE_STEPS\n"; !strings.Contains(buf.String(), want) {
+		t.Errorf("writeConfigTemplate() missing line %q, got:\n%s", want, buf.String())
+	}
+	if stepsFlag.EnvVar != "" {
+		t.Errorf("EnvVar modified: %v", stepsFlag.EnvVar)
+	}
+}
+
+func TestStripJSONComments(t *testing.T) {
+	tests := []struct {
+		name string
+		json string
+		want string
+	}{
+		{
+			name: "line comments",
+			json: "{ // comment\n\"a\": 1 // comment\n}",
+			want: "{ \n\"a\": 1 \n}",
+		},
+		{
+			name: "comment in string",
+			json: `{"url": "https://example.com"}`,
+			want: `{"url": "https://example.com"}`,
+		},
+		{
+			name: "trailing commas",
+			json: "{\"a\": [1, 2,], \"b\": 1, // comment\n}",
+			want: "{\"a\": [1, 2], \"b\": 1 \n}",
+		},
+		{
+			name: "escaped quote",
+			json: `{"a": "\",//"}`,
+			want: `{"a": "\",//"}`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := string(stripJSONComments([]byte(tt.json))); got != tt.want {
+				t.Errorf("stripJSONComments() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
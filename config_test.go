@@ -0,0 +1,137 @@
+package cli
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestCmd_run_configFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.json")
+	config := `{
+		"db-conn": "postgres://config",
+		"env": "staging",
+		"count": 2,
+		"db": {
+			"migrate": {
+				"steps": 3
+			}
+		}
+	}`
+	if err := os.WriteFile(path, []byte(config), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	t.Setenv("TEST_CONFIG_ENV", "production")
+
+	dbConnFlag := &StringFlag{Name: "db-conn"}
+	envFlag := &StringFlag{Name: "env", EnvVar: "TEST_CONFIG_ENV"}
+	countFlag := &IntFlag{Name: "count", Value: 1}
+	stepsFlag := &IntFlag{Name: "steps"}
+	missingFlag := &StringFlag{Name: "missing", Value: "default"}
+
+	var actionCtx *Context
+	root := Cmd{
+		Name: "myapp",
+		SubCmds: []Cmd{{
+			Name: "db",
+			SubCmds: []Cmd{{
+				Name:     "migrate",
+				ReqFlags: []Flag{dbConnFlag},
+				OptFlags: []Flag{envFlag, countFlag, stepsFlag, missingFlag},
+				ActionCtx: func(ctx *Context) error {
+					actionCtx = ctx
+					return nil
+				},
+			}},
+		}},
+		app: &App{Name: "myapp", ConfigFile: path},
+	}
+
+	if err := root.run([]string{"db", "migrate", "--count=5"}, []string{"myapp"}); err != nil {
+		t.Fatalf("run() unexpected error = %v", err)
+	}
+
+	if dbConnFlag.Value != "postgres://config" {
+		t.Errorf("config: Value = %v, want %v", dbConnFlag.Value, "postgres://config")
+	}
+	if envFlag.Value != "production" {
+		t.Errorf("env takes precedence over config: Value = %v, want %v", envFlag.Value, "production")
+	}
+	if countFlag.Value != 5 {
+		t.Errorf("args take precedence over config: Value = %v, want %v", countFlag.Value, 5)
+	}
+	if stepsFlag.Value != 3 {
+		t.Errorf("nested key: Value = %v, want %v", stepsFlag.Value, 3)
+	}
+	if missingFlag.Value != "default" {
+		t.Errorf("default: Value = %v, want %v", missingFlag.Value, "default")
+	}
+	if got := actionCtx.Provenance(stepsFlag); got.Source != SourceConfig || !strings.HasSuffix(got.Key, ":db.migrate.steps") {
+		t.Errorf("Provenance() = %v, want config source with key db.migrate.steps", got)
+	}
+}
+
+func TestCmd_run_configFileError(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.json")
+	if err := os.WriteFile(path, []byte(`{"count": "lots"}`), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	cmd := Cmd{
+		Name:     "myapp",
+		OptFlags: []Flag{&IntFlag{Name: "count"}},
+		Action:   func(args map[string]string) {},
+		app:      &App{Name: "myapp", ConfigFile: path},
+	}
+
+	err := cmd.run(nil, []string{"myapp"})
+	if err == nil {
+		t.Fatal("run() expected error")
+	}
+	if !strings.Contains(err.Error(), path) || !strings.Contains(err.Error(), "'count'") {
+		t.Errorf("run() error = %v, want it to contain the file and key", err)
+	}
+}
+
+func Test_stripJSONComments(t *testing.T) {
+	tests := []struct {
+		name string
+		in   string
+		want string
+	}{
+		{
+			name: "strict json",
+			in:   `{"a": [1, 2]}`,
+			want: `{"a": [1, 2]}`,
+		},
+		{
+			name: "line comments",
+			in:   "{\n// the endpoint\n\"url\": \"https://example.com\" // trailing\n}",
+			want: "{\n\n\"url\": \"https://example.com\" \n}",
+		},
+		{
+			name: "trailing commas",
+			in:   "{\"a\": [1, 2,],\n\"b\": 3,\n}",
+			want: "{\"a\": [1, 2],\n\"b\": 3\n}",
+		},
+		{
+			name: "comma and slashes in strings",
+			in:   `{"a": ",}", "b": "\"//"}`,
+			want: `{"a": ",}", "b": "\"//"}`,
+		},
+		{
+			name: "block comments are not removed",
+			in:   `{/* a */}`,
+			want: `{/* a */}`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := string(stripJSONComments([]byte(tt.in))); got != tt.want {
+				t.Errorf("stripJSONComments() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
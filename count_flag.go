@@ -0,0 +1,158 @@
+package cli
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// CountFlag counts the number of times a flag is provided, eg: for verbosity levels.
+// All of the following result in a Value of 3:
+//
+//	-v -v -v
+//	-vvv
+//	--verbose --verbose --verbose
+//	--verbose=3
+//	-v=3
+//
+// Values from other sources, eg: the env var, are parsed as an int.
+type CountFlag struct {
+	Name        string
+	Alias       string // clustered aliases (eg: -vvv) require a single character alias
+	EnvVar      string
+	EnvVars     []string // fallback env vars, checked in order if EnvVar is not set
+	Description string
+	Max         int                   // if greater than 0, the count is capped at Max
+	Validate    func(value int) error // see ValidatedFlag
+	Value       int                   // can provide a default value here
+}
+
+func (flag *CountFlag) GetName() string {
+	return flag.Name
+}
+
+func (flag *CountFlag) GetAlias() string {
+	return flag.Alias
+}
+
+func (flag *CountFlag) GetEnvVars() []string {
+	return envVarList(flag.EnvVar, flag.EnvVars)
+}
+
+func (flag *CountFlag) GetDescription() string {
+	desc := flag.Description
+
+	desc = addEnvVarDesc(desc, flag.GetEnvVars())
+
+	if flag.Max > 0 {
+		desc = addDescLine(desc, fmt.Sprintf("> max: %d", flag.Max))
+	}
+
+	return desc
+}
+
+func (flag *CountFlag) GetDefault() string {
+	if flag.Value == 0 {
+		return ""
+	}
+
+	return strconv.Itoa(flag.Value)
+}
+
+func (flag *CountFlag) GetPlaceholder() string {
+	return ""
+}
+
+func (flag *CountFlag) Load(argFound bool, argVal *string) (loaded bool, err error) {
+	if !argFound {
+		return false, nil
+	}
+
+	count := 1
+	if argVal != nil {
+		count, err = parseCount(*argVal)
+		if err != nil {
+			return false, err
+		}
+	}
+
+	flag.setValue(count)
+	return true, nil
+}
+
+func (flag *CountFlag) ValidateValue() error {
+	return runValidate(flag.Validate, flag.Value)
+}
+
+// LookupArgs implements ArgsFlag, counting all occurrences of the flag
+func (flag *CountFlag) LookupArgs(flagArgs []string) (key string, val *string, found bool, err error) {
+	var names []string
+	if flag.Name != "" {
+		names = append(names, formatFlag(flag.Name))
+	}
+	if flag.Alias != "" {
+		names = append(names, formatAlias(flag.Alias))
+	}
+
+	var keys []string
+	count := 0
+	for _, arg := range flagArgs {
+		n, ok, err := flag.countArg(arg, names)
+		if err != nil {
+			return arg, nil, false, err
+		}
+
+		if ok {
+			keys = append(keys, arg)
+			count += n
+		}
+	}
+
+	if len(keys) == 0 {
+		return "", nil, false, nil
+	}
+
+	countStr := strconv.Itoa(count)
+	return strings.Join(keys, " "), &countStr, true, nil
+}
+
+// countArg returns the count represented by a single arg, and whether the arg refers to this flag
+func (flag *CountFlag) countArg(arg string, names []string) (count int, ok bool, err error) {
+	for _, name := range names {
+		if arg == name {
+			return 1, true, nil
+		}
+
+		if strings.HasPrefix(arg, name+"=") {
+			count, err = parseCount(strings.TrimPrefix(arg, name+"="))
+			return count, true, err
+		}
+	}
+
+	// clustered aliases, eg: -vvv
+	if len(flag.Alias) == 1 && len(arg) > 2 && !strings.HasPrefix(arg, "--") {
+		cluster := strings.TrimPrefix(arg, "-")
+		if strings.Trim(cluster, flag.Alias) == "" {
+			return len(cluster), true, nil
+		}
+	}
+
+	return 0, false, nil
+}
+
+func (flag *CountFlag) setValue(count int) {
+	if flag.Max > 0 && count > flag.Max {
+		count = flag.Max
+	}
+
+	flag.Value = count
+}
+
+func parseCount(val string) (int, error) {
+	count, err := strconv.Atoi(val)
+	if err != nil || count < 0 {
+		return 0, fmt.Errorf("'%s' is not a valid count", val)
+	}
+
+	return count, nil
+}
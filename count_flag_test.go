@@ -0,0 +1,102 @@
+package cli
+
+import (
+	"testing"
+)
+
+func TestCountFlag(t *testing.T) {
+	tests := []struct {
+		name       string
+		max        int
+		env        string
+		args       []string
+		wantLoaded bool
+		wantValue  int
+		wantErr    bool
+	}{
+		{
+			name:       "not provided",
+			args:       []string{"--other"},
+			wantLoaded: false,
+			wantValue:  0,
+		},
+		{
+			name:       "single",
+			args:       []string{"-v"},
+			wantLoaded: true,
+			wantValue:  1,
+		},
+		{
+			name:       "repeated",
+			args:       []string{"-v", "--other", "--verbose", "-v"},
+			wantLoaded: true,
+			wantValue:  3,
+		},
+		{
+			name:       "clustered",
+			args:       []string{"-vvv"},
+			wantLoaded: true,
+			wantValue:  3,
+		},
+		{
+			name:       "explicit",
+			args:       []string{"--verbose=3"},
+			wantLoaded: true,
+			wantValue:  3,
+		},
+		{
+			name:       "capped",
+			max:        2,
+			args:       []string{"-vvvv"},
+			wantLoaded: true,
+			wantValue:  2,
+		},
+		{
+			name:       "env var",
+			env:        "2",
+			wantLoaded: true,
+			wantValue:  2,
+		},
+		{
+			name:       "args take precedence over env var",
+			env:        "2",
+			args:       []string{"-v"},
+			wantLoaded: true,
+			wantValue:  1,
+		},
+		{
+			name:    "invalid",
+			args:    []string{"--verbose=lots"},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			flag := CountFlag{
+				Name:   "verbose",
+				Alias:  "v",
+				EnvVar: "TEST_COUNT_FLAG_VERBOSE",
+				Max:    tt.max,
+			}
+			env := map[string]string{}
+			if tt.env != "" {
+				env[flag.EnvVar] = tt.env
+			}
+
+			ctx, err := runTestCmdCtx(tt.args, env, &flag)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("run() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if tt.wantErr {
+				return
+			}
+			if gotLoaded := ctx.IsSet(&flag); gotLoaded != tt.wantLoaded {
+				t.Errorf("run() loaded = %v, want %v", gotLoaded, tt.wantLoaded)
+			}
+			if flag.Value != tt.wantValue {
+				t.Errorf("run() Value = %v, want %v", flag.Value, tt.wantValue)
+			}
+		})
+	}
+}
@@ -0,0 +1,285 @@
+package cli
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"strings"
+	"unicode"
+)
+
+// loadDotEnv reads the env vars in each of the dotenv files, for App.DotEnvFiles. Files that don't exist are skipped.
+// When multiple files set the same env var, the first file takes precedence (or the last file, if override is true).
+// Variables are expanded with lookupEnv, and the values of the files read before.
+func loadDotEnv(paths []string, override bool, lookupEnv func(name string) (string, bool)) (map[string]string, error) {
+	vars := make(map[string]string)
+	lookup := dotEnvLookup(vars, override, lookupEnv)
+	for _, path := range paths {
+		fileVars, err := readDotEnv(path, lookup, override)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, v := range fileVars {
+			if _, ok := vars[v.name]; ok && !override {
+				continue
+			}
+			vars[v.name] = v.value
+		}
+	}
+
+	return vars, nil
+}
+
+// dotEnvLookup reads env vars from the dotenv vars and lookupEnv.
+// Env vars that are already set take precedence, unless override is true.
+func dotEnvLookup(vars map[string]string, override bool, lookupEnv func(name string) (string, bool)) func(name string) (string, bool) {
+	return func(name string) (string, bool) {
+		if val, ok := vars[name]; ok && override {
+			return val, true
+		}
+		if val, ok := lookupEnv(name); ok {
+			return val, true
+		}
+		val, ok := vars[name]
+		return val, ok
+	}
+}
+
+// readDotEnvFiles reads the env vars defined in dotenv files. Files that don't exist are skipped.
+// When multiple files define the same env var, the first file takes precedence.
+func readDotEnvFiles(paths []string, lookupEnv func(name string) (string, bool)) (map[string]string, error) {
+	vars := make(map[string]string)
+	for _, path := range paths {
+		fileVars, err := readDotEnv(path, lookupEnv, true)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, v := range fileVars {
+			if _, ok := vars[v.name]; !ok {
+				vars[v.name] = v.value
+			}
+		}
+	}
+
+	return vars, nil
+}
+
+// readDotEnv reads and parses a dotenv file, see parseDotEnv. If the file doesn't exist, nil is returned.
+func readDotEnv(path string, lookup func(name string) (string, bool), override bool) ([]dotEnvVar, error) {
+	path, err := expandPath(path, lookup)
+	if err != nil {
+		return nil, fmt.Errorf("dotenv file '%s': %w", path, err)
+	}
+
+	b, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("dotenv file: %w", err)
+	}
+
+	vars, err := parseDotEnv(string(b), lookup, override)
+	if err != nil {
+		return nil, fmt.Errorf("dotenv file '%s': %w", path, err)
+	}
+
+	return vars, nil
+}
+
+type dotEnvVar struct {
+	name  string
+	value string
+}
+
+// parseDotEnv parses the contents of a dotenv file:
+//
+//	# comments and blank lines are ignored
+//	DB_CONN=postgres://localhost      # unquoted values are trimmed, inline comments are removed
+//	export ENV=dev                    # the export keyword is optional
+//	GREETING="hello\nworld"           # double quoted values support escapes (\n, \t, \", \\, \$)
+//	PATTERN='$literal'                # single quoted values are used as is
+//	URL=${DB_CONN}/db                 # $NAME and ${NAME} are expanded, except in single quotes
+//	PASSWORD=pa$$word                 # other $ signs (and $$) are kept as is
+//	KEY="-----BEGIN KEY-----
+//	...
+//	-----END KEY-----"                # quoted values can span multiple lines
+//
+// Variables are expanded using values defined earlier in the file, or lookup.
+// Values from lookup take precedence, unless override is true.
+func parseDotEnv(contents string, lookup func(name string) (string, bool), override bool) ([]dotEnvVar, error) {
+	var vars []dotEnvVar
+	defined := map[string]string{}
+
+	expandLookup := func(name string) string {
+		if val, ok := defined[name]; ok && override {
+			return val
+		}
+		if val, ok := lookup(name); ok {
+			return val
+		}
+		return defined[name]
+	}
+
+	s := strings.ReplaceAll(contents, "\r\n", "\n")
+	line := 1
+	for len(s) > 0 {
+		// skip blank lines and comments
+		row, rest, _ := strings.Cut(s, "\n")
+		trimmed := strings.TrimSpace(row)
+		if trimmed == "" || strings.HasPrefix(trimmed, "#") {
+			s = rest
+			line++
+			continue
+		}
+
+		s = strings.TrimLeftFunc(s, unicode.IsSpace)
+		s = strings.TrimPrefix(s, "export ")
+
+		name, after, ok := strings.Cut(s, "=")
+		name = strings.TrimSpace(name)
+		if !ok || strings.Contains(name, "\n") {
+			return nil, fmt.Errorf("line %d: expected NAME=value", line)
+		}
+		if !validEnvVarName(name) {
+			return nil, fmt.Errorf("line %d: invalid env var name '%s'", line, name)
+		}
+
+		value, rest, lines, err := parseDotEnvValue(after, expandLookup)
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %s: %w", line, name, err)
+		}
+
+		vars = append(vars, dotEnvVar{name: name, value: value})
+		defined[name] = value
+		s = rest
+		line += lines
+	}
+
+	return vars, nil
+}
+
+// parseDotEnvValue parses a value up to the end of the line (or the closing quote),
+// returning the remaining input and the number of lines consumed.
+func parseDotEnvValue(s string, lookup func(name string) string) (value, rest string, lines int, err error) {
+	s = strings.TrimLeft(s, " \t")
+
+	if len(s) == 0 || (s[0] != '"' && s[0] != '\'') {
+		row, rest, _ := strings.Cut(s, "\n")
+		if i := strings.Index(row, " #"); i >= 0 {
+			row = row[:i]
+		}
+
+		return expandDotEnv(strings.TrimSpace(row), lookup), rest, 1, nil
+	}
+
+	quote := s[0]
+	var b strings.Builder
+	for i := 1; i < len(s); i++ {
+		c := s[i]
+
+		switch {
+		case c == quote:
+			lines = 1 + strings.Count(s[:i], "\n")
+			row, rest, _ := strings.Cut(s[i+1:], "\n")
+			if trailing := strings.TrimSpace(row); trailing != "" && !strings.HasPrefix(trailing, "#") {
+				return "", "", 0, fmt.Errorf("unexpected characters after closing quote: '%s'", trailing)
+			}
+
+			value = b.String()
+			if quote == '"' {
+				value = expandDotEnv(value, lookup)
+			}
+			return value, rest, lines, nil
+
+		case c == '\\' && quote == '"' && i+1 < len(s):
+			i++
+			switch s[i] {
+			case 'n':
+				b.WriteByte('\n')
+			case 'r':
+				b.WriteByte('\r')
+			case 't':
+				b.WriteByte('\t')
+			case '$':
+				// escaped so that it is not expanded, restored by expandDotEnv
+				b.WriteString(escapedDollar)
+			default:
+				b.WriteByte(s[i])
+			}
+
+		default:
+			b.WriteByte(c)
+		}
+	}
+
+	return "", "", 0, fmt.Errorf("missing closing quote (%c)", quote)
+}
+
+// escapedDollar is a placeholder for \$ in double quoted values
+const escapedDollar = "\x00"
+
+// expandDotEnv replaces $NAME and ${NAME} with the value of the variable.
+// Unlike os.Expand, a $ that isn't followed by a name is kept as is, eg: "pa$$word" or "$5".
+func expandDotEnv(s string, lookup func(name string) string) string {
+	var b strings.Builder
+	for {
+		i := strings.IndexByte(s, '$')
+		if i < 0 {
+			b.WriteString(s)
+			break
+		}
+		b.WriteString(s[:i])
+		s = s[i+1:]
+
+		if strings.HasPrefix(s, "$") {
+			b.WriteString("$$")
+			s = s[1:]
+			continue
+		}
+
+		if strings.HasPrefix(s, "{") {
+			name, rest, ok := strings.Cut(s[1:], "}")
+			if ok && validEnvVarName(name) {
+				b.WriteString(lookup(name))
+				s = rest
+				continue
+			}
+		}
+
+		n := 0
+		for n < len(s) && (s[n] == '_' || isASCIILetter(s[n]) || (n > 0 && '0' <= s[n] && s[n] <= '9')) {
+			n++
+		}
+		if n == 0 {
+			b.WriteByte('$')
+			continue
+		}
+		b.WriteString(lookup(s[:n]))
+		s = s[n:]
+	}
+
+	return strings.ReplaceAll(b.String(), escapedDollar, "$")
+}
+
+func isASCIILetter(c byte) bool {
+	return ('a' <= c && c <= 'z') || ('A' <= c && c <= 'Z')
+}
+
+func validEnvVarName(name string) bool {
+	if name == "" {
+		return false
+	}
+
+	for i, r := range name {
+		if r == '_' || unicode.IsLetter(r) || (i > 0 && (unicode.IsDigit(r) || r == '.')) {
+			continue
+		}
+		return false
+	}
+
+	return true
+}
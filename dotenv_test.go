@@ -0,0 +1,145 @@
+package cli
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestParseDotEnv(t *testing.T) {
+	env := map[string]string{"HOME": "/home/gopher", "ENV": "prod"}
+	lookup := func(name string) (string, bool) {
+		val, ok := env[name]
+		return val, ok
+	}
+
+	tests := []struct {
+		name     string
+		contents string
+		override bool
+		want     []dotEnvVar
+		wantErr  bool
+	}{
+		{
+			name: "unquoted",
+			contents: `
+# comment
+DB_CONN=postgres://localhost   # inline comment
+export COUNT = 3
+EMPTY=
+`,
+			want: []dotEnvVar{{"DB_CONN", "postgres://localhost"}, {"COUNT", "3"}, {"EMPTY", ""}},
+		},
+		{
+			name:     "double quoted",
+			contents: `GREETING="hello # not a comment\n\"world\""`,
+			want:     []dotEnvVar{{"GREETING", "hello # not a comment\n\"world\""}},
+		},
+		{
+			name:     "single quoted",
+			contents: `PATTERN='$HOME\n'`,
+			want:     []dotEnvVar{{"PATTERN", `$HOME\n`}},
+		},
+		{
+			name:     "multi-line",
+			contents: "KEY=\"-----BEGIN KEY-----\nabc\n-----END KEY-----\"\nNEXT=1",
+			want:     []dotEnvVar{{"KEY", "-----BEGIN KEY-----\nabc\n-----END KEY-----"}, {"NEXT", "1"}},
+		},
+		{
+			name:     "expansion",
+			contents: "DIR=${HOME}/app\nCONFIG=\"$DIR/config.json\"\nPRICE=\"\\$5\"",
+			want:     []dotEnvVar{{"DIR", "/home/gopher/app"}, {"CONFIG", "/home/gopher/app/config.json"}, {"PRICE", "$5"}},
+		},
+		{
+			name:     "literal dollar signs",
+			contents: "PASSWORD=pa$$word\nPRICE=$5\nTRAILING=cost$\nBRACES=\"${not valid}\"",
+			want:     []dotEnvVar{{"PASSWORD", "pa$$word"}, {"PRICE", "$5"}, {"TRAILING", "cost$"}, {"BRACES", "${not valid}"}},
+		},
+		{
+			name:     "environment takes precedence when expanding",
+			contents: "ENV=dev\nURL=https://$ENV.example.com",
+			want:     []dotEnvVar{{"ENV", "dev"}, {"URL", "https://prod.example.com"}},
+		},
+		{
+			name:     "file takes precedence when expanding with override",
+			contents: "ENV=dev\nURL=https://$ENV.example.com",
+			override: true,
+			want:     []dotEnvVar{{"ENV", "dev"}, {"URL", "https://dev.example.com"}},
+		},
+		{
+			name:     "missing closing quote",
+			contents: `KEY="abc`,
+			wantErr:  true,
+		},
+		{
+			name:     "missing value",
+			contents: `KEY`,
+			wantErr:  true,
+		},
+		{
+			name:     "invalid name",
+			contents: `1KEY=abc`,
+			wantErr:  true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := parseDotEnv(tt.contents, lookup, tt.override)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("parseDotEnv() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("parseDotEnv() got = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestApp_DotEnvFiles(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	path := filepath.Join(dir, ".env")
+	contents := "DB_CONN=postgres://dotenv\nENV=dev\n"
+	if err := os.WriteFile(path, []byte(contents), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name     string
+		override bool
+		wantEnv  string
+	}{
+		{name: "existing env var", override: false, wantEnv: "prod"},
+		{name: "override", override: true, wantEnv: "dev"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dbConnFlag := &StringFlag{Name: "db-conn", EnvVar: "DB_CONN"}
+			envFlag := &StringFlag{Name: "env", EnvVar: "ENV"}
+			app := &App{
+				Name:           "test",
+				OptFlags:       []Flag{dbConnFlag, envFlag},
+				Action:         func(map[string]string) {},
+				DotEnvFiles:    []string{filepath.Join(dir, "missing.env"), path},
+				DotEnvOverride: tt.override,
+				LookupEnv:      MapEnv(map[string]string{"ENV": "prod"}),
+			}
+			if err := app.RunArgs(nil); err != nil {
+				t.Fatal(err)
+			}
+
+			if dbConnFlag.Value != "postgres://dotenv" {
+				t.Errorf("db-conn = %v, want %v", dbConnFlag.Value, "postgres://dotenv")
+			}
+			if envFlag.Value != tt.wantEnv {
+				t.Errorf("env = %v, want %v", envFlag.Value, tt.wantEnv)
+			}
+			if _, ok := os.LookupEnv("DB_CONN"); ok {
+				t.Errorf("DB_CONN set in the environment of the process")
+			}
+		})
+	}
+}
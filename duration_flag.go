@@ -0,0 +1,70 @@
+package cli
+
+import (
+	"fmt"
+	"time"
+)
+
+// DurationFlag is a flag for durations, eg: timeouts.
+// The value can be provided by cli args or env var, in the format accepted by time.ParseDuration, eg: 1m30s.
+// CLI args take precedence.
+type DurationFlag struct {
+	Name        string
+	Alias       string
+	EnvVar      string
+	EnvVars     []string // fallback env vars, checked in order if EnvVar is not set
+	Description string
+	Validate    func(value time.Duration) error // see ValidatedFlag
+	Value       time.Duration                   // can provide a default value here
+}
+
+func (flag *DurationFlag) GetName() string {
+	return flag.Name
+}
+
+func (flag *DurationFlag) GetAlias() string {
+	return flag.Alias
+}
+
+func (flag *DurationFlag) GetEnvVars() []string {
+	return envVarList(flag.EnvVar, flag.EnvVars)
+}
+
+func (flag *DurationFlag) GetDescription() string {
+	desc := flag.Description
+
+	desc = addEnvVarDesc(desc, flag.GetEnvVars())
+
+	return desc
+}
+
+func (flag *DurationFlag) GetDefault() string {
+	if flag.Value == 0 {
+		return ""
+	}
+
+	return flag.Value.String()
+}
+
+func (flag *DurationFlag) GetPlaceholder() string {
+	return "duration"
+}
+
+func (flag *DurationFlag) Load(argFound bool, argVal *string) (loaded bool, err error) {
+	val, found, err := flagValue(flag.Name, argFound, argVal)
+	if !found || err != nil {
+		return found, err
+	}
+
+	d, err := time.ParseDuration(val)
+	if err != nil {
+		return false, fmt.Errorf("'%s' is not a valid duration, eg: 1m30s", val)
+	}
+
+	flag.Value = d
+	return true, nil
+}
+
+func (flag *DurationFlag) ValidateValue() error {
+	return runValidate(flag.Validate, flag.Value)
+}
@@ -0,0 +1,36 @@
+package cli
+
+import (
+	"testing"
+	"time"
+)
+
+func TestDurationFlag_Load(t *testing.T) {
+	tests := []struct {
+		in      string
+		want    time.Duration
+		wantErr bool
+	}{
+		{in: "1s", want: time.Second},
+		{in: "1m30s", want: 90 * time.Second},
+		{in: "1.5h", want: 90 * time.Minute},
+		{in: "250ms", want: 250 * time.Millisecond},
+		{in: "-5m", want: -5 * time.Minute},
+		{in: "0", want: 0},
+		{in: "10", wantErr: true},
+		{in: "1d", wantErr: true},
+		{in: "abc", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.in, func(t *testing.T) {
+			flag := DurationFlag{Name: "timeout"}
+			_, err := flag.Load(true, &tt.in)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Load() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !tt.wantErr && flag.Value != tt.want {
+				t.Errorf("Load() Value = %v, want %v", flag.Value, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,201 @@
+package cli
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// EnumValue is a single accepted value of an EnumFlag.
+type EnumValue[T any] struct {
+	Token       string // the string provided on the cli, eg: "json"
+	Value       T      // the value the token maps to
+	Description string // used for documentation
+}
+
+// EnumFlag maps a set of string tokens to typed values of type T.
+// The value can be provided by cli args or env var.
+// CLI args take precedence.
+// Each accepted value is listed in the help text, along with its description.
+// If an unknown token is provided, the error suggests the closest accepted tokens.
+type EnumFlag[T any] struct {
+	Name            string
+	Alias           string
+	EnvVar          string
+	EnvVars         []string // fallback env vars, checked in order if EnvVar is not set
+	Description     string
+	Values          []EnumValue[T]      // the accepted values
+	CaseInsensitive bool                // if true, tokens are matched case-insensitively
+	Validate        func(value T) error // see ValidatedFlag
+	Value           T                   // can provide a default value here
+	Token           string              // the token that was loaded, if any
+}
+
+func (flag *EnumFlag[T]) GetName() string {
+	return flag.Name
+}
+
+func (flag *EnumFlag[T]) GetAlias() string {
+	return flag.Alias
+}
+
+func (flag *EnumFlag[T]) GetEnvVars() []string {
+	return envVarList(flag.EnvVar, flag.EnvVars)
+}
+
+func (flag *EnumFlag[T]) GetDescription() string {
+	desc := flag.Description
+
+	desc = addEnvVarDesc(desc, flag.GetEnvVars())
+
+	if len(flag.Values) == 0 {
+		return desc
+	}
+
+	desc = addDescLine(desc, "> accepted values:")
+
+	tokenWidth := 0
+	for _, v := range flag.Values {
+		if len(v.Token) > tokenWidth {
+			tokenWidth = len(v.Token)
+		}
+	}
+
+	for _, v := range flag.Values {
+		line := "    " + v.Token
+		if v.Description != "" {
+			line += strings.Repeat(" ", tokenWidth-len(v.Token)) + "  " + v.Description
+		}
+		desc = addDescLine(desc, line)
+	}
+
+	return desc
+}
+
+func (flag *EnumFlag[T]) GetDefault() string {
+	for _, v := range flag.Values {
+		if reflect.DeepEqual(v.Value, flag.Value) {
+			return v.Token
+		}
+	}
+
+	return ""
+}
+
+func (flag *EnumFlag[T]) GetPlaceholder() string {
+	return "value"
+}
+
+func (flag *EnumFlag[T]) Load(argFound bool, argVal *string) (loaded bool, err error) {
+	val, found, err := flagValue(flag.Name, argFound, argVal)
+	if !found || err != nil {
+		return found, err
+	}
+
+	for _, v := range flag.Values {
+		if v.Token == val || (flag.CaseInsensitive && strings.EqualFold(v.Token, val)) {
+			flag.Value = v.Value
+			flag.Token = v.Token
+			return true, nil
+		}
+	}
+
+	return false, flag.unknownTokenErr(val)
+}
+
+func (flag *EnumFlag[T]) ValidateValue() error {
+	return runValidate(flag.Validate, flag.Value)
+}
+
+func (flag *EnumFlag[T]) unknownTokenErr(val string) error {
+	tokens := make([]string, len(flag.Values))
+	for i, v := range flag.Values {
+		tokens[i] = v.Token
+	}
+
+	suggestions := suggest(val, tokens, flag.CaseInsensitive)
+	if len(suggestions) > 0 {
+		return fmt.Errorf("'%s' is not an accepted value, did you mean '%s'? accepted values: [%s]",
+			val, strings.Join(suggestions, "' or '"), strings.Join(tokens, ", "))
+	}
+
+	return fmt.Errorf("'%s' is not an accepted value, accepted values: [%s]", val, strings.Join(tokens, ", "))
+}
+
+// suggest returns the options that are closest to val, if any are close enough to be a likely typo
+func suggest(val string, options []string, caseInsensitive bool) []string {
+	if caseInsensitive {
+		val = strings.ToLower(val)
+	}
+
+	// allow roughly one edit for every three characters
+	maxDist := len(val) / 3
+	if maxDist < 1 {
+		maxDist = 1
+	}
+	bestDist := maxDist + 1
+
+	var best []string
+	for _, option := range options {
+		cmp := option
+		if caseInsensitive {
+			cmp = strings.ToLower(cmp)
+		}
+
+		dist := levenshtein(val, cmp)
+		if strings.HasPrefix(cmp, val) && dist > 1 {
+			// a prefix of an option is always a reasonable suggestion
+			dist = 1
+		}
+
+		if dist > maxDist {
+			continue
+		}
+
+		if dist < bestDist {
+			bestDist = dist
+			best = []string{option}
+		} else if dist == bestDist {
+			best = append(best, option)
+		}
+	}
+
+	return best
+}
+
+// levenshtein returns the edit distance between a and b
+func levenshtein(a, b string) int {
+	ra, rb := []rune(a), []rune(b)
+
+	prev := make([]int, len(rb)+1)
+	curr := make([]int, len(rb)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+
+	for i := 1; i <= len(ra); i++ {
+		curr[0] = i
+		for j := 1; j <= len(rb); j++ {
+			cost := 1
+			if ra[i-1] == rb[j-1] {
+				cost = 0
+			}
+
+			curr[j] = minInt(prev[j]+1, curr[j-1]+1, prev[j-1]+cost)
+		}
+		prev, curr = curr, prev
+	}
+
+	return prev[len(rb)]
+}
+
+func minInt(vals ...int) int {
+	m := vals[0]
+	for _, v := range vals[1:] {
+		if v < m {
+			m = v
+		}
+	}
+
+	return m
+}
@@ -0,0 +1,97 @@
+package cli
+
+import (
+	"reflect"
+	"testing"
+)
+
+type testFormat int
+
+const (
+	testFormatText testFormat = iota
+	testFormatJSON
+	testFormatYAML
+)
+
+func TestEnumFlag_Load(t *testing.T) {
+	values := []EnumValue[testFormat]{
+		{Token: "text", Value: testFormatText},
+		{Token: "json", Value: testFormatJSON},
+		{Token: "yaml", Value: testFormatYAML},
+	}
+
+	tests := []struct {
+		name            string
+		caseInsensitive bool
+		argVal          string
+		wantValue       testFormat
+		wantErr         bool
+	}{
+		{
+			name:      "exact match",
+			argVal:    "json",
+			wantValue: testFormatJSON,
+		},
+		{
+			name:    "case mismatch",
+			argVal:  "JSON",
+			wantErr: true,
+		},
+		{
+			name:            "case insensitive",
+			caseInsensitive: true,
+			argVal:          "YAML",
+			wantValue:       testFormatYAML,
+		},
+		{
+			name:    "unknown",
+			argVal:  "xml",
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			flag := EnumFlag[testFormat]{
+				Name:            "format",
+				Values:          values,
+				CaseInsensitive: tt.caseInsensitive,
+			}
+
+			loaded, err := flag.Load(true, &tt.argVal)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Load() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if tt.wantErr {
+				return
+			}
+			if !loaded {
+				t.Errorf("Load() loaded = false, want true")
+			}
+			if flag.Value != tt.wantValue {
+				t.Errorf("Load() Value = %v, want %v", flag.Value, tt.wantValue)
+			}
+		})
+	}
+}
+
+func Test_suggest(t *testing.T) {
+	options := []string{"json", "yaml", "text", "table"}
+
+	tests := []struct {
+		val  string
+		want []string
+	}{
+		{val: "jsn", want: []string{"json"}},
+		{val: "yml", want: []string{"yaml"}},
+		{val: "tab", want: []string{"table"}},
+		{val: "xml", want: nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.val, func(t *testing.T) {
+			if got := suggest(tt.val, options, false); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("suggest() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
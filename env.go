@@ -0,0 +1,142 @@
+package cli
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+)
+
+// autoEnvVar returns the env var name derived for a flag that doesn't specify an env var (see App.AutoEnv), if any.
+// Names are derived from the prefix, the command path (optional) and the flag name,
+// eg: MYAPP_DB_MIGRATE_STEPS for prefix MYAPP, command path "db migrate" and flag "steps".
+func (cmd *Cmd) autoEnvVar(fl Flag) string {
+	if cmd.app == nil || !cmd.app.AutoEnv || fl.GetName() == "" {
+		return ""
+	}
+
+	ef, ok := flagAs[EnvVarFlag](fl)
+	if !ok || len(ef.GetEnvVars()) > 0 {
+		return ""
+	}
+
+	parts := []string{cmd.app.EnvPrefix}
+	if cmd.app.AutoEnvCmdPath {
+		parts = append(parts, cmd.namePath...)
+	}
+
+	return envVarName(append(parts, fl.GetName())...)
+}
+
+// autoEnvFlags documents the derived env vars of flags in their description, see autoEnvVar
+func (cmd *Cmd) autoEnvFlags(flags []Flag) []Flag {
+	described := make([]Flag, 0, len(flags))
+	for _, fl := range flags {
+		if name := cmd.autoEnvVar(fl); name != "" {
+			fl = &describedFlag{Flag: fl, description: addEnvVarDesc(fl.GetDescription(), []string{name})}
+		}
+		described = append(described, fl)
+	}
+
+	return described
+}
+
+// envVarName joins parts into an env var name, eg: ("myapp", "db-conn") => MYAPP_DB_CONN
+func envVarName(parts ...string) string {
+	var name []string
+	for _, part := range parts {
+		if part == "" {
+			continue
+		}
+
+		part = strings.Map(func(r rune) rune {
+			if unicode.IsLetter(r) || unicode.IsDigit(r) {
+				return unicode.ToUpper(r)
+			}
+			return '_'
+		}, part)
+
+		name = append(name, part)
+	}
+
+	return strings.Join(name, "_")
+}
+
+// envVarList returns the primary env var followed by the fallback env vars, ignoring empty names
+func envVarList(envVar string, fallbacks []string) []string {
+	var names []string
+	for _, name := range append([]string{envVar}, fallbacks...) {
+		if name != "" {
+			names = append(names, name)
+		}
+	}
+
+	return names
+}
+
+// lookupEnvVars returns the value of the first env var in names that is set to a non-empty value.
+// Following the docker convention, each env var can also be provided as a file, by setting <NAME>_FILE
+// to the path of the file. It is an error to set both <NAME> and <NAME>_FILE. The file is read once,
+// and its contents are returned as the value.
+// name is the env var the value was loaded from, eg: DB_CONN or DB_CONN_FILE.
+func lookupEnvVars(lookup func(name string) (string, bool), names []string) (name string, val *string, found bool, err error) {
+	for _, name := range names {
+		val, _ := lookup(name)
+		path, _ := lookup(name + "_FILE")
+
+		if val != "" && path != "" {
+			return name, nil, false, fmt.Errorf("both %s and %s_FILE are set, only one may be used", name, name)
+		}
+
+		if val != "" {
+			return name, &val, true, nil
+		}
+
+		if path != "" {
+			val, err := readValueFile(path)
+			if err != nil {
+				return name + "_FILE", nil, false, fmt.Errorf("loading %s_FILE: %w", name, err)
+			}
+
+			// an empty file is treated like an empty env var, ie: the next env var is checked
+			if val != "" {
+				return name + "_FILE", &val, true, nil
+			}
+		}
+	}
+
+	return "", nil, false, nil
+}
+
+// MapEnv returns a LookupEnv function that reads env vars from a map instead of the environment of the process,
+// eg: for App.LookupEnv in tests
+func MapEnv(env map[string]string) func(name string) (string, bool) {
+	return func(name string) (string, bool) {
+		val, ok := env[name]
+		return val, ok
+	}
+}
+
+// flagValue returns the value provided for a flag. An empty value is treated as not found.
+func flagValue(name string, argFound bool, argVal *string) (val string, found bool, err error) {
+	if !argFound {
+		return "", false, nil
+	}
+
+	if argVal == nil {
+		return "", true, fmt.Errorf("flag %s is missing a value", name)
+	}
+
+	return *argVal, *argVal != "", nil
+}
+
+// addEnvVarDesc documents the env vars a flag can be loaded from
+func addEnvVarDesc(desc string, names []string) string {
+	switch len(names) {
+	case 0:
+		return desc
+	case 1:
+		return addDescLine(desc, "> env var: "+names[0])
+	default:
+		return addDescLine(desc, "> env vars: "+strings.Join(names, ", "))
+	}
+}
@@ -0,0 +1,237 @@
+package cli
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func Test_envVarName(t *testing.T) {
+	tests := []struct {
+		parts []string
+		want  string
+	}{
+		{parts: []string{"", "db-conn"}, want: "DB_CONN"},
+		{parts: []string{"myapp", "db-conn"}, want: "MYAPP_DB_CONN"},
+		{parts: []string{"myapp", "db", "migrate", "steps"}, want: "MYAPP_DB_MIGRATE_STEPS"},
+		{parts: []string{"my-app", "gcp.creds"}, want: "MY_APP_GCP_CREDS"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.want, func(t *testing.T) {
+			if got := envVarName(tt.parts...); got != tt.want {
+				t.Errorf("envVarName() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCmd_run_autoEnv(t *testing.T) {
+	t.Setenv("MYAPP_DATABASE_MIGRATE_STEPS", "3")
+	t.Setenv("LEGACY_DB_CONN", "postgres://legacy")
+
+	stepsFlag := &IntFlag{Name: "steps"}
+	dbConnFlag := &StringFlag{Name: "db-conn", EnvVar: "LEGACY_DB_CONN"}
+
+	app := &App{
+		Name:           "myapp",
+		EnvPrefix:      "MYAPP",
+		AutoEnv:        true,
+		AutoEnvCmdPath: true,
+	}
+	root := Cmd{
+		Name: app.Name,
+		SubCmds: []Cmd{{
+			Name:  "database",
+			Alias: "db",
+			SubCmds: []Cmd{{
+				Name:     "migrate",
+				OptFlags: []Flag{stepsFlag, dbConnFlag},
+				Action:   func(args map[string]string) {},
+			}},
+		}},
+		app: app,
+	}
+
+	if err := root.run([]string{"db", "migrate"}, []string{app.Name}); err != nil {
+		t.Fatalf("run() unexpected error = %v", err)
+	}
+
+	if stepsFlag.EnvVar != "" {
+		t.Errorf("EnvVar modified: %v", stepsFlag.EnvVar)
+	}
+	if stepsFlag.Value != 3 {
+		t.Errorf("Value = %v, want %v", stepsFlag.Value, 3)
+	}
+	if dbConnFlag.Value != "postgres://legacy" {
+		t.Errorf("explicit EnvVar not used, Value = %v", dbConnFlag.Value)
+	}
+}
+
+func TestStringFlag_envVarFallback(t *testing.T) {
+	t.Setenv("TEST_FALLBACK_DATABASE_URL", "postgres://legacy")
+
+	dbConnFlag := &StringFlag{
+		Name:    "db-conn",
+		EnvVar:  "TEST_FALLBACK_DB_CONN",
+		EnvVars: []string{"TEST_FALLBACK_DATABASE_URL"},
+	}
+
+	var actionCtx *Context
+	cmd := Cmd{
+		Name:     "test",
+		OptFlags: []Flag{dbConnFlag},
+		ActionCtx: func(ctx *Context) error {
+			actionCtx = ctx
+			return nil
+		},
+	}
+	if err := cmd.run(nil, []string{"test"}); err != nil {
+		t.Fatalf("run() unexpected error = %v", err)
+	}
+
+	if dbConnFlag.Value != "postgres://legacy" {
+		t.Errorf("Value = %v, want %v", dbConnFlag.Value, "postgres://legacy")
+	}
+	if got := actionCtx.Provenance(dbConnFlag).Key; got != "TEST_FALLBACK_DATABASE_URL" {
+		t.Errorf("Provenance().Key = %v, want %v", got, "TEST_FALLBACK_DATABASE_URL")
+	}
+
+	t.Setenv("TEST_FALLBACK_DB_CONN", "postgres://new")
+	if err := cmd.run(nil, []string{"test"}); err != nil {
+		t.Fatalf("run() unexpected error = %v", err)
+	}
+	if dbConnFlag.Value != "postgres://new" {
+		t.Errorf("Value = %v, want %v", dbConnFlag.Value, "postgres://new")
+	}
+}
+
+func Test_lookupEnvVars_file(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "db_conn")
+	if err := os.WriteFile(path, []byte("postgres://secret\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	t.Setenv("TEST_LOOKUP_DB_CONN_FILE", path)
+
+	name, val, found, err := lookupEnvVars(os.LookupEnv, []string{"TEST_LOOKUP_DB_CONN"})
+	if err != nil || !found {
+		t.Fatalf("lookupEnvVars() found = %v, err = %v", found, err)
+	}
+	if name != "TEST_LOOKUP_DB_CONN_FILE" || *val != "postgres://secret" {
+		t.Errorf("lookupEnvVars() = %q, %q, want %q, %q", name, *val, "TEST_LOOKUP_DB_CONN_FILE", "postgres://secret")
+	}
+
+	t.Setenv("TEST_LOOKUP_DB_CONN", "postgres://env")
+	if _, _, _, err := lookupEnvVars(os.LookupEnv, []string{"TEST_LOOKUP_DB_CONN"}); err == nil {
+		t.Errorf("lookupEnvVars() expected error when both env var and _FILE are set")
+	}
+}
+
+func Test_lookupEnvVars_emptyFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "db_conn")
+	if err := os.WriteFile(path, []byte("\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	env := MapEnv(map[string]string{
+		"DB_CONN_FILE": path,
+		"DATABASE_URL": "postgres://fallback",
+	})
+
+	name, val, found, err := lookupEnvVars(env, []string{"DB_CONN", "DATABASE_URL"})
+	if err != nil || !found {
+		t.Fatalf("lookupEnvVars() found = %v, err = %v", found, err)
+	}
+	if name != "DATABASE_URL" || *val != "postgres://fallback" {
+		t.Errorf("lookupEnvVars() = %q, %q, want %q, %q", name, *val, "DATABASE_URL", "postgres://fallback")
+	}
+
+	_, _, found, err = lookupEnvVars(env, []string{"DB_CONN"})
+	if err != nil || found {
+		t.Errorf("lookupEnvVars() found = %v, err = %v, want not found", found, err)
+	}
+}
+
+func TestCmd_run_lookupEnv(t *testing.T) {
+	profileDir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(profileDir, "staging.json"), []byte(`{"region": "staging-region"}`), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	type config struct {
+		Name  string `json:"name"`
+		Level int    `json:"level"`
+	}
+
+	tests := []struct {
+		name       string
+		env        map[string]string
+		wantName   string
+		wantLevel  int
+		wantJSON   config
+		wantDir    string
+		wantRegion string
+	}{
+		{
+			name:       "env",
+			env:        map[string]string{"NAME": "env-name", "LEVEL": "3", "CONFIG": `{"name": "env"}`, "DATA": "/data", "PROFILES": profileDir},
+			wantName:   "env-name",
+			wantLevel:  3,
+			wantJSON:   config{Name: "env", Level: 1},
+			wantDir:    "/data/cache",
+			wantRegion: "default-region",
+		},
+		{
+			name:       "profile",
+			env:        map[string]string{"MYAPP_PROFILE": "staging", "PROFILES": profileDir},
+			wantName:   "default",
+			wantJSON:   config{Level: 1},
+			wantDir:    "/cache",
+			wantRegion: "staging-region",
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			nameFlag := &StringFlag{Name: "name", EnvVar: "NAME", Value: "default"}
+			levelFlag := &CountFlag{Name: "level", EnvVar: "LEVEL"}
+			jsonFlag := &JSONFlag[config]{Name: "config", EnvVar: "CONFIG", Merge: true, Value: config{Level: 1}}
+			dirFlag := &PathFlag{Name: "dir", Value: "$DATA/cache"}
+			regionFlag := &StringFlag{Name: "region", Value: "default-region"}
+
+			cmd := Cmd{
+				Name:     "myapp",
+				OptFlags: []Flag{nameFlag, levelFlag, jsonFlag, dirFlag, regionFlag},
+				Action:   func(args map[string]string) {},
+				app: &App{
+					Name:       "myapp",
+					Profiles:   true,
+					ProfileDir: "$PROFILES",
+					LookupEnv:  MapEnv(tt.env),
+				},
+			}
+
+			if err := cmd.run(nil, []string{"myapp"}); err != nil {
+				t.Fatalf("run() unexpected error = %v", err)
+			}
+
+			if nameFlag.Value != tt.wantName {
+				t.Errorf("StringFlag Value = %v, want %v", nameFlag.Value, tt.wantName)
+			}
+			if levelFlag.Value != tt.wantLevel {
+				t.Errorf("CountFlag Value = %v, want %v", levelFlag.Value, tt.wantLevel)
+			}
+			if jsonFlag.Value != tt.wantJSON {
+				t.Errorf("JSONFlag Value = %v, want %v", jsonFlag.Value, tt.wantJSON)
+			}
+			if dirFlag.Value != tt.wantDir {
+				t.Errorf("PathFlag Value = %v, want %v", dirFlag.Value, tt.wantDir)
+			}
+			if regionFlag.Value != tt.wantRegion {
+				t.Errorf("profile: Value = %v, want %v", regionFlag.Value, tt.wantRegion)
+			}
+		})
+	}
+}
@@ -0,0 +1,168 @@
+package cli
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+)
+
+// FileFlag is a flag that takes the path to a file.
+// The value can be provided by cli args or env var.
+// CLI args take precedence.
+// A value of '-' refers to stdin.
+// The file is validated at load time and can optionally be opened or read.
+// The default value is not validated, opened or read if the flag is not provided.
+type FileFlag struct {
+	Name        string
+	Alias       string
+	EnvVar      string
+	EnvVars     []string // fallback env vars, checked in order if EnvVar is not set
+	Description string
+	MustExist   bool                     // if true, the file must exist
+	Readable    bool                     // if true, the file must exist and be readable
+	Writable    bool                     // if true and the file exists, it must be writable
+	Open        bool                     // if true, the file is opened for reading and made available in File
+	Read        bool                     // if true, the file contents are read into Contents
+	Validate    func(value string) error // see ValidatedFlag
+	Value       string                   // path to the file, can provide a default value here
+
+	File     *os.File // the opened file, if Open is set. The caller is responsible for closing it.
+	Contents []byte   // the contents of the file, if Read is set
+}
+
+func (flag *FileFlag) GetName() string {
+	return flag.Name
+}
+
+func (flag *FileFlag) GetAlias() string {
+	return flag.Alias
+}
+
+func (flag *FileFlag) GetEnvVars() []string {
+	return envVarList(flag.EnvVar, flag.EnvVars)
+}
+
+func (flag *FileFlag) GetDescription() string {
+	desc := flag.Description
+
+	desc = addEnvVarDesc(desc, flag.GetEnvVars())
+
+	return desc
+}
+
+func (flag *FileFlag) GetDefault() string {
+	return flag.Value
+}
+
+func (flag *FileFlag) GetPlaceholder() string {
+	return "file"
+}
+
+func (flag *FileFlag) Load(argFound bool, argVal *string) (loaded bool, err error) {
+	stdin := func(name string) (io.Reader, error) {
+		return os.Stdin, nil
+	}
+
+	return flag.loadWith(loadContext{lookupEnv: os.LookupEnv, stdin: stdin}, argFound, argVal)
+}
+
+// loadWith implements contextLoader, reading stdin at most once per command
+func (flag *FileFlag) loadWith(lctx loadContext, argFound bool, argVal *string) (loaded bool, err error) {
+	val, found, err := flagValue(flag.Name, argFound, argVal)
+	if err != nil || !found {
+		return found, err
+	}
+
+	flag.Value = val
+	if err := flag.load(lctx); err != nil {
+		return false, err
+	}
+
+	return true, nil
+}
+
+func (flag *FileFlag) ValidateValue() error {
+	return runValidate(flag.Validate, flag.Value)
+}
+
+func (flag *FileFlag) load(lctx loadContext) error {
+	if flag.Value == "-" {
+		if !flag.Open && !flag.Read {
+			return nil
+		}
+
+		stdin, err := lctx.stdin(flag.Name)
+		if err != nil {
+			return err
+		}
+
+		if flag.Open {
+			f, ok := stdin.(*os.File)
+			if !ok {
+				return errors.New("stdin is not a file")
+			}
+			flag.File = f
+		}
+
+		if flag.Read {
+			b, err := io.ReadAll(stdin)
+			if err != nil {
+				return fmt.Errorf("reading stdin: %w", err)
+			}
+			flag.Contents = b
+		}
+
+		return nil
+	}
+
+	info, err := os.Stat(flag.Value)
+	if os.IsNotExist(err) {
+		if flag.MustExist || flag.Readable || flag.Open || flag.Read {
+			return fmt.Errorf("file '%s' does not exist", flag.Value)
+		}
+
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("file '%s': %w", flag.Value, err)
+	}
+
+	if info.IsDir() {
+		return fmt.Errorf("'%s' is a directory, not a file", flag.Value)
+	}
+
+	if flag.Readable {
+		f, err := os.Open(flag.Value)
+		if err != nil {
+			return fmt.Errorf("file '%s' is not readable: %w", flag.Value, err)
+		}
+		f.Close()
+	}
+
+	if flag.Writable {
+		f, err := os.OpenFile(flag.Value, os.O_WRONLY, 0)
+		if err != nil {
+			return fmt.Errorf("file '%s' is not writable: %w", flag.Value, err)
+		}
+		f.Close()
+	}
+
+	if flag.Read {
+		b, err := os.ReadFile(flag.Value)
+		if err != nil {
+			return fmt.Errorf("reading file: %w", err)
+		}
+		flag.Contents = b
+	}
+
+	if flag.Open {
+		f, err := os.Open(flag.Value)
+		if err != nil {
+			return fmt.Errorf("opening file: %w", err)
+		}
+		flag.File = f
+	}
+
+	return nil
+}
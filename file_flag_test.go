@@ -0,0 +1,182 @@
+package cli
+
+import (
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestFileFlag_Load(t *testing.T) {
+	dir := t.TempDir()
+	file := filepath.Join(dir, "file.txt")
+	if err := os.WriteFile(file, []byte("contents"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	readOnly := filepath.Join(dir, "read-only.txt")
+	if err := os.WriteFile(readOnly, nil, 0o400); err != nil {
+		t.Fatal(err)
+	}
+	writeOnly := filepath.Join(dir, "write-only.txt")
+	if err := os.WriteFile(writeOnly, nil, 0o200); err != nil {
+		t.Fatal(err)
+	}
+	missing := filepath.Join(dir, "missing.txt")
+
+	// permissions are not enforced for root
+	isRoot := os.Geteuid() == 0
+
+	tests := []struct {
+		name         string
+		flag         FileFlag
+		argFound     bool
+		argVal       string
+		wantLoaded   bool
+		wantContents string
+		wantErr      bool
+		skip         bool
+	}{
+		{
+			name:       "exists",
+			flag:       FileFlag{Name: "file", MustExist: true},
+			argFound:   true,
+			argVal:     file,
+			wantLoaded: true,
+		},
+		{
+			name:       "missing",
+			flag:       FileFlag{Name: "file"},
+			argFound:   true,
+			argVal:     missing,
+			wantLoaded: true,
+		},
+		{
+			name:     "must exist",
+			flag:     FileFlag{Name: "file", MustExist: true},
+			argFound: true,
+			argVal:   missing,
+			wantErr:  true,
+		},
+		{
+			name:     "directory",
+			flag:     FileFlag{Name: "file"},
+			argFound: true,
+			argVal:   dir,
+			wantErr:  true,
+		},
+		{
+			name:       "readable",
+			flag:       FileFlag{Name: "file", Readable: true},
+			argFound:   true,
+			argVal:     readOnly,
+			wantLoaded: true,
+		},
+		{
+			name:     "not readable",
+			flag:     FileFlag{Name: "file", Readable: true},
+			argFound: true,
+			argVal:   writeOnly,
+			wantErr:  true,
+			skip:     isRoot,
+		},
+		{
+			name:       "writable",
+			flag:       FileFlag{Name: "file", Writable: true},
+			argFound:   true,
+			argVal:     writeOnly,
+			wantLoaded: true,
+		},
+		{
+			name:     "not writable",
+			flag:     FileFlag{Name: "file", Writable: true},
+			argFound: true,
+			argVal:   readOnly,
+			wantErr:  true,
+			skip:     isRoot,
+		},
+		{
+			name:       "writable and missing",
+			flag:       FileFlag{Name: "file", Writable: true},
+			argFound:   true,
+			argVal:     missing,
+			wantLoaded: true,
+		},
+		{
+			name:         "read",
+			flag:         FileFlag{Name: "file", Read: true},
+			argFound:     true,
+			argVal:       file,
+			wantLoaded:   true,
+			wantContents: "contents",
+		},
+		{
+			name:         "open",
+			flag:         FileFlag{Name: "file", Open: true},
+			argFound:     true,
+			argVal:       file,
+			wantLoaded:   true,
+			wantContents: "contents",
+		},
+		{
+			name:     "open missing",
+			flag:     FileFlag{Name: "file", Open: true},
+			argFound: true,
+			argVal:   missing,
+			wantErr:  true,
+		},
+		{
+			name:         "read stdin",
+			flag:         FileFlag{Name: "file", Read: true},
+			argFound:     true,
+			argVal:       "-",
+			wantLoaded:   true,
+			wantContents: "from stdin",
+		},
+		{
+			name:       "default is not validated",
+			flag:       FileFlag{Name: "file", Value: missing, MustExist: true, Open: true},
+			wantLoaded: false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if tt.skip {
+				t.Skip("file permissions are not enforced")
+			}
+
+			r := &argValueResolver{stdin: strings.NewReader("from stdin")}
+			lctx := loadContext{lookupEnv: MapEnv(nil), stdin: r.openStdin}
+
+			var argVal *string
+			if tt.argFound {
+				argVal = &tt.argVal
+			}
+
+			loaded, err := tt.flag.loadWith(lctx, tt.argFound, argVal)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Load() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+			if loaded != tt.wantLoaded {
+				t.Errorf("Load() loaded = %v, want %v", loaded, tt.wantLoaded)
+			}
+
+			contents := string(tt.flag.Contents)
+			if tt.flag.File != nil {
+				defer tt.flag.File.Close()
+
+				b, err := io.ReadAll(tt.flag.File)
+				if err != nil {
+					t.Fatal(err)
+				}
+				contents = string(b)
+			}
+			if contents != tt.wantContents {
+				t.Errorf("Load() contents = %q, want %q", contents, tt.wantContents)
+			}
+		})
+	}
+}
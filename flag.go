@@ -20,6 +20,12 @@ import (
 //	-f='value'
 //	-f="value"
 //	-f
+//
+// Flags wrapped with FromFile can also read their value from a file or stdin:
+//
+//	--flag=@path/to/file
+//	--flag=-
+//	--flag=@@value   (escaped, the value is '@value')
 type Flag interface {
 	GetName() string
 	GetAlias() string
@@ -29,10 +35,124 @@ type Flag interface {
 	// and argVal contains the value provided for the flag, if any.
 	// Should return true if the flag was loaded  (used for required/optional validation)
 	// Returns an error if the flag was found but the value was invalid etc.
-	// This method can be used to load the flag from any source, not just cli args. Eg: env vars, files etc.
+	// When a command runs, the value found in the first value source that has one (see App.ValueSources)
+	// is passed as argVal, eg: from cli args, env vars or a config file.
+	// Load(false, nil) is called if none of the sources have a value, eg: to load the flag from a file instead.
 	Load(argFound bool, argVal *string) (loaded bool, err error)
 }
 
+// DefaultValueFlag can be implemented by flags to document their default value
+// and the type of value they expect. This is used in the help text, eg:
+//
+//	--count=<int>    Number of items (default: 5)
+//
+// All built-in flag types implement this interface.
+type DefaultValueFlag interface {
+	Flag
+
+	// GetDefault returns the default value formatted for documentation.
+	// Should return an empty string for zero values and secrets.
+	GetDefault() string
+
+	// GetPlaceholder returns a short description of the expected value, eg: "int".
+	// Should return an empty string for flags that don't take a value, eg: BoolFlag.
+	GetPlaceholder() string
+}
+
+// ArgsFlag can be implemented by flags that need to inspect all flag args,
+// instead of only the first occurrence of the flag. Eg: to count repeated flags.
+// If implemented, ArgsSource looks up the value of the flag with LookupArgs, which is then passed to Load.
+type ArgsFlag interface {
+	Flag
+
+	// LookupArgs returns the value of the flag from all flag args provided to the command.
+	// key describes the args the value was found in, eg: "-v -v".
+	// Return values have the same meaning as for ValueSource.Lookup.
+	LookupArgs(flagArgs []string) (key string, val *string, found bool, err error)
+}
+
+// ValidatedFlag can be implemented by flags that validate their value after it has been loaded.
+// ValidateValue is only called if the flag was loaded successfully.
+// All built-in flag types implement this interface: if their Validate field is specified,
+// it is called with the loaded value and a returned error fails the command, like a load error.
+type ValidatedFlag interface {
+	Flag
+
+	ValidateValue() error
+}
+
+// WithValidate wraps a flag, adding a validate func that is called after the flag is loaded.
+// This is useful to add validation to custom flag types, without implementing ValidatedFlag.
+func WithValidate(fl Flag, validate func() error) Flag {
+	return &validateFlag{
+		Flag:     fl,
+		validate: validate,
+	}
+}
+
+type validateFlag struct {
+	Flag
+	validate func() error
+}
+
+func (flag *validateFlag) ValidateValue() error {
+	if flag.validate == nil {
+		return nil
+	}
+
+	return flag.validate()
+}
+
+// Unwrap returns the wrapped flag
+func (flag *validateFlag) Unwrap() Flag {
+	return flag.Flag
+}
+
+// flagAs finds the first flag in fl's chain of wrapped flags that implements T
+func flagAs[T any](fl Flag) (T, bool) {
+	for fl != nil {
+		if t, ok := any(fl).(T); ok {
+			return t, true
+		}
+
+		w, ok := fl.(interface{ Unwrap() Flag })
+		if !ok {
+			break
+		}
+		fl = w.Unwrap()
+	}
+
+	var zero T
+	return zero, false
+}
+
+// validateFlagChain calls ValidateValue on fl and every flag it wraps
+func validateFlagChain(fl Flag) error {
+	for fl != nil {
+		if v, ok := fl.(ValidatedFlag); ok {
+			if err := v.ValidateValue(); err != nil {
+				return err
+			}
+		}
+
+		w, ok := fl.(interface{ Unwrap() Flag })
+		if !ok {
+			break
+		}
+		fl = w.Unwrap()
+	}
+
+	return nil
+}
+
+func runValidate[T any](validate func(T) error, val T) error {
+	if validate == nil {
+		return nil
+	}
+
+	return validate(val)
+}
+
 // LoadFlagFromArgs will load a flag from cli args.
 // Returns true if the flag was found, false otherwise.
 // If the flag was found, value will contain the value provided for the flag, if any.
@@ -55,6 +175,15 @@ func LoadFlagFromArgs(name, alias string, args []string) (found bool, value *str
 	return false, nil, nil
 }
 
+// addDescLine appends a line to a flag description
+func addDescLine(desc, line string) string {
+	if desc == "" {
+		return line
+	}
+
+	return desc + "\n" + line
+}
+
 func formatFlag(name string) string {
 	return "--" + name
 }
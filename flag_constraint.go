@@ -0,0 +1,136 @@
+package cli
+
+import (
+	"fmt"
+	"strings"
+)
+
+// FlagConstraint is a constraint between flags of a command, eg: flags that are mutually exclusive.
+// Constraints are validated after all flags have been loaded, a flag is considered set if it was loaded.
+// The flags must also be listed in the command's ReqFlags or OptFlags.
+// Use MutuallyExclusive, RequiredTogether, AtLeastOneOf, ExactlyOneOf or RequiredIf to create a constraint.
+type FlagConstraint struct {
+	kind   constraintKind
+	flags  []Flag
+	ifFlag Flag // only used by RequiredIf
+}
+
+type constraintKind int
+
+const (
+	mutuallyExclusive constraintKind = iota
+	requiredTogether
+	atLeastOneOf
+	exactlyOneOf
+	requiredIf
+)
+
+// MutuallyExclusive flags can not be provided together
+func MutuallyExclusive(flags ...Flag) FlagConstraint {
+	return FlagConstraint{kind: mutuallyExclusive, flags: flags}
+}
+
+// RequiredTogether flags must all be provided if any one of them is provided
+func RequiredTogether(flags ...Flag) FlagConstraint {
+	return FlagConstraint{kind: requiredTogether, flags: flags}
+}
+
+// AtLeastOneOf the flags must be provided
+func AtLeastOneOf(flags ...Flag) FlagConstraint {
+	return FlagConstraint{kind: atLeastOneOf, flags: flags}
+}
+
+// ExactlyOneOf the flags must be provided
+func ExactlyOneOf(flags ...Flag) FlagConstraint {
+	return FlagConstraint{kind: exactlyOneOf, flags: flags}
+}
+
+// RequiredIf flag must be provided if ifFlag is provided
+func RequiredIf(flag Flag, ifFlag Flag) FlagConstraint {
+	return FlagConstraint{kind: requiredIf, flags: []Flag{flag}, ifFlag: ifFlag}
+}
+
+// check validates the constraint against the set of loaded flags, by flagKey
+func (c FlagConstraint) check(loaded map[string]bool) error {
+	var set, unset []Flag
+	for _, fl := range c.flags {
+		if loaded[flagKey(fl)] {
+			set = append(set, fl)
+		} else {
+			unset = append(unset, fl)
+		}
+	}
+
+	switch c.kind {
+	case mutuallyExclusive:
+		if len(set) > 1 {
+			return fmt.Errorf("flags %s are mutually exclusive", joinFlagNames(set, " and "))
+		}
+
+	case requiredTogether:
+		if len(set) > 0 && len(unset) > 0 {
+			return fmt.Errorf("flags %s must be provided together, missing: %s",
+				joinFlagNames(c.flags, ", "), joinFlagNames(unset, ", "))
+		}
+
+	case atLeastOneOf:
+		if len(set) == 0 {
+			return fmt.Errorf("at least one of %s must be provided", joinFlagNames(c.flags, ", "))
+		}
+
+	case exactlyOneOf:
+		if len(set) == 0 {
+			return fmt.Errorf("exactly one of %s must be provided", joinFlagNames(c.flags, ", "))
+		}
+		if len(set) > 1 {
+			return fmt.Errorf("exactly one of %s must be provided, got: %s",
+				joinFlagNames(c.flags, ", "), joinFlagNames(set, ", "))
+		}
+
+	case requiredIf:
+		if loaded[flagKey(c.ifFlag)] && len(unset) > 0 {
+			return fmt.Errorf("flag %s is required when %s is provided",
+				joinFlagNames(c.flags, ""), joinFlagNames([]Flag{c.ifFlag}, ""))
+		}
+	}
+
+	return nil
+}
+
+// String describes the constraint, used for documentation
+func (c FlagConstraint) String() string {
+	names := joinFlagNames(c.flags, ", ")
+
+	switch c.kind {
+	case mutuallyExclusive:
+		return "mutually exclusive: " + names
+	case requiredTogether:
+		return "required together: " + names
+	case atLeastOneOf:
+		return "at least one of: " + names
+	case exactlyOneOf:
+		return "exactly one of: " + names
+	case requiredIf:
+		return fmt.Sprintf("%s is required when %s is provided", names, joinFlagNames([]Flag{c.ifFlag}, ""))
+	}
+
+	return names
+}
+
+func joinFlagNames(flags []Flag, sep string) string {
+	names := make([]string, len(flags))
+	for i, fl := range flags {
+		names[i] = flagKey(fl)
+	}
+
+	return strings.Join(names, sep)
+}
+
+// flagKey identifies a flag within a command by its formatted name (or alias), since flags may not be comparable
+func flagKey(fl Flag) string {
+	if fl.GetName() == "" {
+		return formatAlias(fl.GetAlias())
+	}
+
+	return formatFlag(fl.GetName())
+}
@@ -0,0 +1,179 @@
+package cli
+
+import (
+	"testing"
+)
+
+func TestFlagConstraint_check(t *testing.T) {
+	file := &StringFlag{Name: "file"}
+	url := &StringFlag{Name: "url"}
+	stdin := &BoolFlag{Name: "stdin"}
+	user := &StringFlag{Name: "user"}
+	password := &SecretFlag{Name: "password"}
+
+	tests := []struct {
+		name       string
+		constraint FlagConstraint
+		loaded     []Flag
+		wantErr    bool
+	}{
+		{
+			name:       "mutually exclusive: none",
+			constraint: MutuallyExclusive(file, url),
+		},
+		{
+			name:       "mutually exclusive: one",
+			constraint: MutuallyExclusive(file, url),
+			loaded:     []Flag{url},
+		},
+		{
+			name:       "mutually exclusive: both",
+			constraint: MutuallyExclusive(file, url),
+			loaded:     []Flag{file, url},
+			wantErr:    true,
+		},
+		{
+			name:       "required together: none",
+			constraint: RequiredTogether(user, password),
+		},
+		{
+			name:       "required together: all",
+			constraint: RequiredTogether(user, password),
+			loaded:     []Flag{user, password},
+		},
+		{
+			name:       "required together: missing",
+			constraint: RequiredTogether(user, password),
+			loaded:     []Flag{password},
+			wantErr:    true,
+		},
+		{
+			name:       "at least one of: none",
+			constraint: AtLeastOneOf(file, url, stdin),
+			wantErr:    true,
+		},
+		{
+			name:       "at least one of: two",
+			constraint: AtLeastOneOf(file, url, stdin),
+			loaded:     []Flag{file, stdin},
+		},
+		{
+			name:       "exactly one of: none",
+			constraint: ExactlyOneOf(file, url, stdin),
+			wantErr:    true,
+		},
+		{
+			name:       "exactly one of: one",
+			constraint: ExactlyOneOf(file, url, stdin),
+			loaded:     []Flag{stdin},
+		},
+		{
+			name:       "exactly one of: two",
+			constraint: ExactlyOneOf(file, url, stdin),
+			loaded:     []Flag{file, url},
+			wantErr:    true,
+		},
+		{
+			name:       "required if: condition not met",
+			constraint: RequiredIf(password, user),
+		},
+		{
+			name:       "required if: provided",
+			constraint: RequiredIf(password, user),
+			loaded:     []Flag{user, password},
+		},
+		{
+			name:       "required if: missing",
+			constraint: RequiredIf(password, user),
+			loaded:     []Flag{user},
+			wantErr:    true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			loaded := make(map[string]bool)
+			for _, fl := range tt.loaded {
+				loaded[flagKey(fl)] = true
+			}
+
+			if err := tt.constraint.check(loaded); (err != nil) != tt.wantErr {
+				t.Errorf("check() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+// namesFlag is a custom flag with a value receiver that isn't comparable, so it can't be used as a map key
+type namesFlag struct {
+	names []string
+	value *string
+}
+
+func (fl namesFlag) GetName() string        { return fl.names[0] }
+func (fl namesFlag) GetAlias() string       { return "" }
+func (fl namesFlag) GetDescription() string { return "" }
+
+func (fl namesFlag) Load(argFound bool, argVal *string) (bool, error) {
+	if !argFound || argVal == nil {
+		return false, nil
+	}
+
+	*fl.value = *argVal
+	return true, nil
+}
+
+func TestCmd_run_constraintsUncomparableFlags(t *testing.T) {
+	var fileVal, urlVal string
+	file := namesFlag{names: []string{"file"}, value: &fileVal}
+	url := namesFlag{names: []string{"url"}, value: &urlVal}
+
+	tests := []struct {
+		name    string
+		args    []string
+		wantErr bool
+	}{
+		{name: "one", args: []string{"--file=a.txt"}},
+		{name: "both", args: []string{"--file=a.txt", "--url=http://a"}, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cmd := Cmd{
+				Name:            "test",
+				OptFlags:        []Flag{file, url},
+				FlagConstraints: []FlagConstraint{MutuallyExclusive(file, url)},
+				Action:          func(args map[string]string) {},
+			}
+
+			err := cmd.run(tt.args, []string{"test"})
+			if (err != nil) != tt.wantErr {
+				t.Errorf("run() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestCmd_run_requiredBoolFlag(t *testing.T) {
+	tests := []struct {
+		name    string
+		args    []string
+		wantErr bool
+	}{
+		{name: "provided", args: []string{"--confirm"}},
+		{name: "explicit false", args: []string{"--confirm=false"}},
+		{name: "not provided", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cmd := Cmd{
+				Name:     "test",
+				ReqFlags: []Flag{&BoolFlag{Name: "confirm"}},
+				Action:   func(args map[string]string) {},
+			}
+
+			err := cmd.run(tt.args, []string{"test"})
+			if (err != nil) != tt.wantErr {
+				t.Errorf("run() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
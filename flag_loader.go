@@ -0,0 +1,259 @@
+package cli
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strings"
+)
+
+// flagLoader loads the flags of a command from its value sources, see App.ValueSources
+type flagLoader struct {
+	sources  []ValueSource // in order of precedence
+	ctx      SourceContext
+	resolver *argValueResolver
+	sets     []setOverride // --set overrides, see JSONFlag
+}
+
+func (cmd *Cmd) newFlagLoader(flagArgs []string) (*flagLoader, error) {
+	loader := &flagLoader{
+		sources:  defaultValueSources(),
+		ctx:      SourceContext{CmdPath: cmd.namePath, FlagArgs: flagArgs},
+		resolver: &argValueResolver{stdin: os.Stdin},
+	}
+
+	if hasSetOverrideFlags(cmd.ReqFlags, cmd.OptFlags) {
+		var err error
+		loader.sets, err = parseSetArgs(flagArgs)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if cmd.app != nil {
+		loader.ctx.lookupEnv = cmd.app.lookupEnv
+		loader.ctx.autoEnvVar = cmd.autoEnvVar
+		if len(cmd.app.ValueSources) > 0 {
+			loader.sources = cmd.app.ValueSources
+		}
+	}
+
+	// commands without flags don't need a profile or config file, eg: the profile commands
+	if cmd.app == nil || len(cmd.ReqFlags)+len(cmd.OptFlags) == 0 {
+		return loader, nil
+	}
+
+	if cmd.app.Profiles && hasValueSource[profileSource](loader.sources) {
+		name, err := cmd.app.activeProfile(flagArgs)
+		if err != nil {
+			return nil, err
+		}
+
+		if name != "" {
+			loader.ctx.profile, err = cmd.app.loadProfile(name)
+			if err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	if hasValueSource[configSource](loader.sources) {
+		config, err := cmd.app.loadConfig()
+		if err != nil {
+			return nil, err
+		}
+		loader.ctx.config = config
+	}
+
+	return loader, nil
+}
+
+// sourceValue is a value of a flag, found in a value source
+type sourceValue struct {
+	Provenance
+	val *string // the value passed to the flag, with file and stdin references resolved
+}
+
+// mergeFlag is implemented by flags that merge the values of all sources, instead of using the first value found,
+// eg: JSONFlag.Merge
+type mergeFlag interface {
+	mergesValues() bool
+
+	// loadMerged loads the flag from the values found in the value sources, in order of precedence
+	loadMerged(values []sourceValue) (loaded bool, err error)
+}
+
+// loadContext provides what the built-in flags need to load, other than their value
+type loadContext struct {
+	lookupEnv func(name string) (string, bool)     // see App.LookupEnv
+	stdin     func(name string) (io.Reader, error) // stdin can only be consumed by a single flag
+}
+
+// contextLoader is implemented by built-in flags that need a loadContext, eg: PathFlag expands env vars.
+// The command calls loadWith instead of Load, so that the flags don't read the environment of the process directly.
+type contextLoader interface {
+	loadWith(lctx loadContext, argFound bool, argVal *string) (loaded bool, err error)
+}
+
+// load loads the flag from the first value source that has a value for it, then validates the flag.
+// If none of the sources have a value, the flag is loaded without one, eg: to use its default value.
+func (l *flagLoader) load(fl Flag) (loaded bool, prov Provenance, err error) {
+	if mf, ok := flagAs[mergeFlag](fl); ok && mf.mergesValues() {
+		return l.loadMerged(fl, mf)
+	}
+
+	for _, src := range l.sources {
+		v, found, err := l.lookup(src, fl)
+		if err != nil {
+			return l.finish(fl, false, v.Provenance, err)
+		}
+		if !found {
+			continue
+		}
+
+		loaded, err = l.loadValue(fl, true, v.val)
+		if err != nil {
+			return l.finish(fl, false, v.Provenance, fmt.Errorf("loading flag: '%s': %s '%s': %w", fl.GetName(), v.Source, v.Key, err))
+		}
+
+		return l.finish(fl, loaded, flagProvenance(fl, loaded, v.Provenance), nil)
+	}
+
+	// the flag may load itself from other sources, eg: SecretFlag.File
+	loaded, err = l.loadValue(fl, false, nil)
+	if err != nil {
+		return false, prov, fmt.Errorf("loading flag: '%s': %w", fl.GetName(), err)
+	}
+
+	return l.finish(fl, loaded, flagProvenance(fl, loaded, Provenance{Source: SourceUnknown}), nil)
+}
+
+// loadValue calls Load, or loadWith for flags that implement contextLoader
+func (l *flagLoader) loadValue(fl Flag, argFound bool, argVal *string) (loaded bool, err error) {
+	if cl, ok := flagAs[contextLoader](fl); ok {
+		return cl.loadWith(loadContext{lookupEnv: l.ctx.LookupEnv, stdin: l.resolver.openStdin}, argFound, argVal)
+	}
+
+	return fl.Load(argFound, argVal)
+}
+
+// loadMerged loads a flag from the values of all sources, see mergeFlag
+func (l *flagLoader) loadMerged(fl Flag, mf mergeFlag) (loaded bool, prov Provenance, err error) {
+	var values []sourceValue
+	for _, src := range l.sources {
+		v, found, err := l.lookup(src, fl)
+		if err != nil {
+			return l.finish(fl, false, v.Provenance, err)
+		}
+		if found {
+			values = append(values, v)
+		}
+	}
+
+	loaded, err = mf.loadMerged(values)
+	if err != nil {
+		return false, prov, fmt.Errorf("loading flag: '%s': %w", fl.GetName(), err)
+	}
+
+	return l.finish(fl, loaded, flagProvenance(fl, loaded, Provenance{Source: SourceUnknown}), nil)
+}
+
+// lookup finds the value of the flag in src, resolving file and stdin references
+func (l *flagLoader) lookup(src ValueSource, fl Flag) (v sourceValue, found bool, err error) {
+	key, val, found, err := src.Lookup(l.ctx, fl)
+	if err != nil {
+		return v, false, fmt.Errorf("loading flag: '%s': %s: %w", fl.GetName(), src.Source(), err)
+	}
+	if !found {
+		return v, false, nil
+	}
+
+	v.Provenance = Provenance{Source: src.Source(), Key: key}
+	if val != nil {
+		v.Raw = *val
+	}
+
+	v.val, err = l.resolver.resolve(fl, val)
+	if err != nil {
+		return v, false, fmt.Errorf("load flag value: '%s': %w", fl.GetName(), err)
+	}
+
+	return v, true, nil
+}
+
+// finish applies --set overrides, validates a loaded flag, sets bound struct fields (see Bind)
+// and warns if the flag is deprecated
+func (l *flagLoader) finish(fl Flag, loaded bool, prov Provenance, err error) (bool, Provenance, error) {
+	if err == nil {
+		loaded, prov, err = l.applySetArgs(fl, loaded, prov)
+	}
+
+	if err != nil || !loaded {
+		return false, prov, err
+	}
+
+	if err := validateFlagChain(fl); err != nil {
+		return false, prov, fmt.Errorf("invalid flag: '%s': %w", fl.GetName(), err)
+	}
+
+	if bf, ok := flagAs[boundValueFlag](fl); ok {
+		if err := bf.bindValue(); err != nil {
+			return false, prov, fmt.Errorf("loading flag: '%s': %w", fl.GetName(), err)
+		}
+	}
+
+	if isDeprecated(fl) {
+		fmt.Fprintln(os.Stderr, "WARNING:", deprecationWarning(fl))
+	}
+
+	return true, prov, nil
+}
+
+// applySetArgs applies --set overrides to the value of the flag, on top of the value loaded from any source.
+// If the flag was not loaded, the overrides are applied to the default value.
+func (l *flagLoader) applySetArgs(fl Flag, loaded bool, prov Provenance) (bool, Provenance, error) {
+	sf, ok := flagAs[setOverrideFlag](fl)
+	if !ok {
+		return loaded, prov, nil
+	}
+
+	var applied []string
+	for _, o := range l.sets {
+		if o.flag != fl.GetName() {
+			continue
+		}
+
+		var path []string
+		if o.field != "" {
+			path = strings.Split(o.field, ".")
+		}
+
+		if err := sf.applySet(path, o.raw); err != nil {
+			return false, prov, fmt.Errorf("loading flag: '%s': --set %s.%s: %w", fl.GetName(), fl.GetName(), o.field, err)
+		}
+
+		applied = append(applied, o.field+"="+o.raw)
+	}
+	if len(applied) == 0 {
+		return loaded, prov, nil
+	}
+
+	if !loaded {
+		prov = Provenance{Source: SourceArgs}
+	}
+	prov.Key = strings.TrimSpace(prov.Key + " " + formatFlag(setFlag.Name))
+	prov.Raw = strings.TrimSpace(prov.Raw + " " + strings.Join(applied, " "))
+
+	return true, prov, nil
+}
+
+// findFlagArg returns the formatted name or alias that was used to provide the flag, eg: --flag or -f
+func findFlagArg(fl Flag, flagArgs []string) string {
+	if fl.GetName() != "" {
+		if found, _, _ := loadFlagFromArgsFormatted(formatFlag(fl.GetName()), flagArgs); found {
+			return formatFlag(fl.GetName())
+		}
+	}
+
+	return formatAlias(fl.GetAlias())
+}
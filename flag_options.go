@@ -0,0 +1,132 @@
+package cli
+
+import (
+	"fmt"
+)
+
+// DeprecatedFlag can be implemented by flags that are deprecated.
+// Deprecated flags are still loaded, but a warning is printed to stderr when they are used,
+// and they are listed in a separate section of the help text.
+type DeprecatedFlag interface {
+	Flag
+
+	// Deprecation returns a message explaining the deprecation and the name of the flag that replaces it, if any.
+	Deprecation() (message string, replacement string)
+}
+
+// HiddenFlag can be implemented by flags that should not be listed in the help text.
+// Hidden flags are still loaded.
+type HiddenFlag interface {
+	Flag
+
+	IsHidden() bool
+}
+
+// FileValueFlag can be implemented by flags whose values can be read from a file or stdin, see FromFile.
+type FileValueFlag interface {
+	Flag
+
+	ValueFromFile() bool
+}
+
+// Deprecate marks a flag as deprecated.
+// replacement is the name of the flag that should be used instead, if any.
+func Deprecate(fl Flag, message string, replacement string) Flag {
+	return &deprecatedFlag{
+		Flag:        fl,
+		message:     message,
+		replacement: replacement,
+	}
+}
+
+type deprecatedFlag struct {
+	Flag
+	message     string
+	replacement string
+}
+
+func (flag *deprecatedFlag) Deprecation() (message string, replacement string) {
+	return flag.message, flag.replacement
+}
+
+// Unwrap returns the wrapped flag
+func (flag *deprecatedFlag) Unwrap() Flag {
+	return flag.Flag
+}
+
+// Hide hides a flag from the help text
+func Hide(fl Flag) Flag {
+	return &hiddenFlag{Flag: fl}
+}
+
+type hiddenFlag struct {
+	Flag
+}
+
+func (flag *hiddenFlag) IsHidden() bool {
+	return true
+}
+
+// Unwrap returns the wrapped flag
+func (flag *hiddenFlag) Unwrap() Flag {
+	return flag.Flag
+}
+
+// FromFile allows the value of a flag to be read from a file or stdin, from any value source:
+//
+//	--flag=@path    the contents of the file at path
+//	--flag=-        the contents of stdin
+//	--flag=@@value  the literal value '@value'
+//
+// Other flags use values as provided, eg: --user=@handle.
+func FromFile(fl Flag) Flag {
+	return &fileValueFlag{Flag: fl}
+}
+
+type fileValueFlag struct {
+	Flag
+}
+
+func (flag *fileValueFlag) ValueFromFile() bool {
+	return true
+}
+
+// Unwrap returns the wrapped flag
+func (flag *fileValueFlag) Unwrap() Flag {
+	return flag.Flag
+}
+
+func isHidden(fl Flag) bool {
+	h, ok := flagAs[HiddenFlag](fl)
+	return ok && h.IsHidden()
+}
+
+func isDeprecated(fl Flag) bool {
+	_, ok := flagAs[DeprecatedFlag](fl)
+	return ok
+}
+
+// deprecationWarning is printed when a deprecated flag is used
+func deprecationWarning(fl Flag) string {
+	return fmt.Sprintf("flag %s is deprecated%s", joinFlagNames([]Flag{fl}, ""), deprecationDetails(fl))
+}
+
+// deprecationDetails describes how a deprecated flag should be replaced
+func deprecationDetails(fl Flag) string {
+	d, ok := flagAs[DeprecatedFlag](fl)
+	if !ok {
+		return ""
+	}
+
+	message, replacement := d.Deprecation()
+
+	var details string
+	if replacement != "" {
+		details += fmt.Sprintf(", use %s instead", formatFlag(replacement))
+	}
+	if message != "" {
+		details += ": " + message
+	}
+
+	return details
+}
@@ -1,3 +1,7 @@
 module github.com/fritzkeyzer/cli
 
 go 1.19
+
+require golang.org/x/term v0.15.0
+
+require golang.org/x/sys v0.15.0 // indirect
@@ -0,0 +1,92 @@
+package cli
+
+import (
+	"fmt"
+	"net"
+	"strconv"
+)
+
+// HostPortFlag is a flag for network addresses in the form host:port, eg: localhost:8080 or [::1]:443.
+// The value can be provided by cli args or env var.
+// CLI args take precedence.
+type HostPortFlag struct {
+	Name        string
+	Alias       string
+	EnvVar      string
+	EnvVars     []string // fallback env vars, checked in order if EnvVar is not set
+	Description string
+	Validate    func(value string) error // see ValidatedFlag
+	Value       string                   // can provide a default value here
+}
+
+func (flag *HostPortFlag) GetName() string {
+	return flag.Name
+}
+
+func (flag *HostPortFlag) GetAlias() string {
+	return flag.Alias
+}
+
+func (flag *HostPortFlag) GetEnvVars() []string {
+	return envVarList(flag.EnvVar, flag.EnvVars)
+}
+
+func (flag *HostPortFlag) GetDescription() string {
+	desc := flag.Description
+
+	desc = addEnvVarDesc(desc, flag.GetEnvVars())
+
+	return desc
+}
+
+func (flag *HostPortFlag) GetDefault() string {
+	return flag.Value
+}
+
+func (flag *HostPortFlag) GetPlaceholder() string {
+	return "host:port"
+}
+
+func (flag *HostPortFlag) Load(argFound bool, argVal *string) (loaded bool, err error) {
+	val, found, err := flagValue(flag.Name, argFound, argVal)
+	if !found || err != nil {
+		return found, err
+	}
+
+	if _, _, err := splitHostPort(val); err != nil {
+		return false, err
+	}
+
+	flag.Value = val
+	return true, nil
+}
+
+func (flag *HostPortFlag) ValidateValue() error {
+	return runValidate(flag.Validate, flag.Value)
+}
+
+// Host returns the host part of Value
+func (flag *HostPortFlag) Host() string {
+	host, _, _ := splitHostPort(flag.Value)
+	return host
+}
+
+// Port returns the port part of Value
+func (flag *HostPortFlag) Port() uint16 {
+	_, port, _ := splitHostPort(flag.Value)
+	return port
+}
+
+func splitHostPort(val string) (host string, port uint16, err error) {
+	host, portStr, err := net.SplitHostPort(val)
+	if err != nil {
+		return "", 0, fmt.Errorf("'%s' is not a valid host:port", val)
+	}
+
+	p, err := strconv.ParseUint(portStr, 10, 16)
+	if err != nil {
+		return "", 0, fmt.Errorf("'%s' is not a valid port", portStr)
+	}
+
+	return host, uint16(p), nil
+}
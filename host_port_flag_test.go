@@ -0,0 +1,45 @@
+package cli
+
+import (
+	"testing"
+)
+
+func TestHostPortFlag_Load(t *testing.T) {
+	tests := []struct {
+		in       string
+		wantHost string
+		wantPort uint16
+		wantErr  bool
+	}{
+		{in: "localhost:8080", wantHost: "localhost", wantPort: 8080},
+		{in: "127.0.0.1:0", wantHost: "127.0.0.1", wantPort: 0},
+		{in: "[::1]:443", wantHost: "::1", wantPort: 443},
+		{in: ":9000", wantHost: "", wantPort: 9000},
+		{in: "localhost", wantErr: true},
+		{in: "::1:443", wantErr: true},
+		{in: "localhost:http", wantErr: true},
+		{in: "localhost:65536", wantErr: true},
+		{in: "localhost:-1", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.in, func(t *testing.T) {
+			flag := HostPortFlag{Name: "addr"}
+			_, err := flag.Load(true, &tt.in)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Load() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+			if flag.Value != tt.in {
+				t.Errorf("Load() Value = %v, want %v", flag.Value, tt.in)
+			}
+			if got := flag.Host(); got != tt.wantHost {
+				t.Errorf("Host() = %v, want %v", got, tt.wantHost)
+			}
+			if got := flag.Port(); got != tt.wantPort {
+				t.Errorf("Port() = %v, want %v", got, tt.wantPort)
+			}
+		})
+	}
+}
@@ -1,17 +1,27 @@
 package cli
 
 import (
+	"errors"
 	"fmt"
 	"strconv"
+	"strings"
 )
 
 // IntFlag can be provided by cli args or env var. Values are parsed as int.
 // CLI args take precedence.
+// Values may be written in decimal, hex (0x1F), octal (0o17) or binary (0b101),
+// and may contain underscores between digits (1_000_000). A leading zero is ignored, eg: 010 is 10.
+// If Min and/or Max are specified, the value is validated against them.
 type IntFlag struct {
 	Name        string
 	Alias       string
+	EnvVar      string
+	EnvVars     []string // fallback env vars, checked in order if EnvVar is not set
 	Description string
-	Value       int // can provide a default value here
+	Min         *int                  // if specified, values below Min are rejected
+	Max         *int                  // if specified, values above Max are rejected
+	Validate    func(value int) error // see ValidatedFlag
+	Value       int                   // can provide a default value here
 }
 
 func (flag *IntFlag) GetName() string {
@@ -22,28 +32,239 @@ func (flag *IntFlag) GetAlias() string {
 	return flag.Alias
 }
 
+func (flag *IntFlag) GetEnvVars() []string {
+	return envVarList(flag.EnvVar, flag.EnvVars)
+}
+
 func (flag *IntFlag) GetDescription() string {
-	desc := flag.Description
+	return intDescription(flag.Description, flag.GetEnvVars(), flag.Min, flag.Max)
+}
 
-	return desc
+func (flag *IntFlag) GetDefault() string {
+	if flag.Value == 0 {
+		return ""
+	}
+
+	return strconv.Itoa(flag.Value)
+}
+
+func (flag *IntFlag) GetPlaceholder() string {
+	return "int"
 }
 
 func (flag *IntFlag) Load(argFound bool, argVal *string) (loaded bool, err error) {
-	if !argFound {
-		return false, nil
+	val, found, err := flagValue(flag.Name, argFound, argVal)
+	if !found || err != nil {
+		return found, err
 	}
-	if argVal == nil {
-		return true, fmt.Errorf("flag %s is missing a value", flag.Name)
+
+	parsed, err := strconv.ParseInt(trimLeadingZeros(val), 0, strconv.IntSize)
+	if err != nil {
+		return false, intParseErr("int", val, err)
 	}
 
-	if *argVal != "" {
-		parsed, err := strconv.ParseInt(*argVal, 10, 64)
-		if err != nil {
-			return false, fmt.Errorf("parsing int: %w", err)
-		}
-		flag.Value = int(parsed)
-		return true, nil
+	if err := checkRange(int(parsed), flag.Min, flag.Max); err != nil {
+		return false, err
 	}
 
-	return false, nil
+	flag.Value = int(parsed)
+	return true, nil
+}
+
+func (flag *IntFlag) ValidateValue() error {
+	return runValidate(flag.Validate, flag.Value)
+}
+
+// Int64Flag is the same as IntFlag, but values are parsed as int64.
+type Int64Flag struct {
+	Name        string
+	Alias       string
+	EnvVar      string
+	EnvVars     []string // fallback env vars, checked in order if EnvVar is not set
+	Description string
+	Min         *int64                  // if specified, values below Min are rejected
+	Max         *int64                  // if specified, values above Max are rejected
+	Validate    func(value int64) error // see ValidatedFlag
+	Value       int64                   // can provide a default value here
+}
+
+func (flag *Int64Flag) GetName() string {
+	return flag.Name
+}
+
+func (flag *Int64Flag) GetAlias() string {
+	return flag.Alias
+}
+
+func (flag *Int64Flag) GetEnvVars() []string {
+	return envVarList(flag.EnvVar, flag.EnvVars)
+}
+
+func (flag *Int64Flag) GetDescription() string {
+	return intDescription(flag.Description, flag.GetEnvVars(), flag.Min, flag.Max)
+}
+
+func (flag *Int64Flag) GetDefault() string {
+	if flag.Value == 0 {
+		return ""
+	}
+
+	return strconv.FormatInt(flag.Value, 10)
+}
+
+func (flag *Int64Flag) GetPlaceholder() string {
+	return "int64"
+}
+
+func (flag *Int64Flag) Load(argFound bool, argVal *string) (loaded bool, err error) {
+	val, found, err := flagValue(flag.Name, argFound, argVal)
+	if !found || err != nil {
+		return found, err
+	}
+
+	parsed, err := strconv.ParseInt(trimLeadingZeros(val), 0, 64)
+	if err != nil {
+		return false, intParseErr("int64", val, err)
+	}
+
+	if err := checkRange(parsed, flag.Min, flag.Max); err != nil {
+		return false, err
+	}
+
+	flag.Value = parsed
+	return true, nil
+}
+
+func (flag *Int64Flag) ValidateValue() error {
+	return runValidate(flag.Validate, flag.Value)
+}
+
+// UintFlag is the same as IntFlag, but values are parsed as uint.
+// Negative values are rejected.
+type UintFlag struct {
+	Name        string
+	Alias       string
+	EnvVar      string
+	EnvVars     []string // fallback env vars, checked in order if EnvVar is not set
+	Description string
+	Min         *uint                  // if specified, values below Min are rejected
+	Max         *uint                  // if specified, values above Max are rejected
+	Validate    func(value uint) error // see ValidatedFlag
+	Value       uint                   // can provide a default value here
+}
+
+func (flag *UintFlag) GetName() string {
+	return flag.Name
+}
+
+func (flag *UintFlag) GetAlias() string {
+	return flag.Alias
+}
+
+func (flag *UintFlag) GetEnvVars() []string {
+	return envVarList(flag.EnvVar, flag.EnvVars)
+}
+
+func (flag *UintFlag) GetDescription() string {
+	return intDescription(flag.Description, flag.GetEnvVars(), flag.Min, flag.Max)
+}
+
+func (flag *UintFlag) GetDefault() string {
+	if flag.Value == 0 {
+		return ""
+	}
+
+	return strconv.FormatUint(uint64(flag.Value), 10)
+}
+
+func (flag *UintFlag) GetPlaceholder() string {
+	return "uint"
+}
+
+func (flag *UintFlag) Load(argFound bool, argVal *string) (loaded bool, err error) {
+	val, found, err := flagValue(flag.Name, argFound, argVal)
+	if !found || err != nil {
+		return found, err
+	}
+
+	parsed, err := strconv.ParseUint(trimLeadingZeros(val), 0, strconv.IntSize)
+	if err != nil {
+		return false, intParseErr("uint", val, err)
+	}
+
+	if err := checkRange(uint(parsed), flag.Min, flag.Max); err != nil {
+		return false, err
+	}
+
+	flag.Value = uint(parsed)
+	return true, nil
+}
+
+func (flag *UintFlag) ValidateValue() error {
+	return runValidate(flag.Validate, flag.Value)
+}
+
+type integer interface {
+	~int | ~int64 | ~uint
+}
+
+// trimLeadingZeros removes leading zeros from decimal values, so that they are not parsed as (legacy) octal,
+// eg: 010 => 10. Values with a 0x, 0o or 0b prefix are returned as is.
+func trimLeadingZeros(val string) string {
+	sign, digits := "", val
+	if strings.HasPrefix(val, "-") || strings.HasPrefix(val, "+") {
+		sign, digits = val[:1], val[1:]
+	}
+
+	if len(digits) < 2 || digits[0] != '0' || !(digits[1] == '_' || ('0' <= digits[1] && digits[1] <= '9')) {
+		return val
+	}
+
+	digits = strings.TrimLeft(digits, "0_")
+	if digits == "" {
+		digits = "0"
+	}
+
+	return sign + digits
+}
+
+// intParseErr converts strconv errors into a readable error
+func intParseErr(typeName, val string, err error) error {
+	if errors.Is(err, strconv.ErrRange) {
+		return fmt.Errorf("'%s' is out of range for %s", val, typeName)
+	}
+
+	return fmt.Errorf("'%s' is not a valid %s", val, typeName)
+}
+
+// checkRange validates that val is within the (optional) min and max bounds
+func checkRange[T integer](val T, min, max *T) error {
+	if min != nil && max != nil && (val < *min || val > *max) {
+		return fmt.Errorf("%d is out of range: must be between %d and %d", val, *min, *max)
+	}
+
+	if min != nil && val < *min {
+		return fmt.Errorf("%d is too small: must be at least %d", val, *min)
+	}
+
+	if max != nil && val > *max {
+		return fmt.Errorf("%d is too large: must be at most %d", val, *max)
+	}
+
+	return nil
+}
+
+func intDescription[T integer](desc string, envVars []string, min, max *T) string {
+	desc = addEnvVarDesc(desc, envVars)
+
+	switch {
+	case min != nil && max != nil:
+		desc = addDescLine(desc, fmt.Sprintf("> range: [%d, %d]", *min, *max))
+	case min != nil:
+		desc = addDescLine(desc, fmt.Sprintf("> min: %d", *min))
+	case max != nil:
+		desc = addDescLine(desc, fmt.Sprintf("> max: %d", *max))
+	}
+
+	return desc
 }
@@ -0,0 +1,246 @@
+package cli
+
+import (
+	"testing"
+)
+
+func intPtr(i int) *int {
+	return &i
+}
+
+func int64Ptr(i int64) *int64 {
+	return &i
+}
+
+func TestIntFlag_Load(t *testing.T) {
+	tests := []struct {
+		name       string
+		flag       IntFlag
+		argFound   bool
+		argVal     *string
+		wantLoaded bool
+		wantValue  int
+		wantErr    bool
+	}{
+		{
+			name:       "not provided keeps default",
+			flag:       IntFlag{Name: "count", Value: 5},
+			wantLoaded: false,
+			wantValue:  5,
+		},
+		{
+			name:       "decimal",
+			flag:       IntFlag{Name: "count"},
+			argFound:   true,
+			argVal:     s("42"),
+			wantLoaded: true,
+			wantValue:  42,
+		},
+		{
+			name:       "hex",
+			flag:       IntFlag{Name: "count"},
+			argFound:   true,
+			argVal:     s("0x1F"),
+			wantLoaded: true,
+			wantValue:  31,
+		},
+		{
+			name:       "octal",
+			flag:       IntFlag{Name: "count"},
+			argFound:   true,
+			argVal:     s("0o17"),
+			wantLoaded: true,
+			wantValue:  15,
+		},
+		{
+			name:       "binary",
+			flag:       IntFlag{Name: "count"},
+			argFound:   true,
+			argVal:     s("0b101"),
+			wantLoaded: true,
+			wantValue:  5,
+		},
+		{
+			name:       "leading zero is decimal",
+			flag:       IntFlag{Name: "count"},
+			argFound:   true,
+			argVal:     s("010"),
+			wantLoaded: true,
+			wantValue:  10,
+		},
+		{
+			name:       "negative leading zero",
+			flag:       IntFlag{Name: "count"},
+			argFound:   true,
+			argVal:     s("-010"),
+			wantLoaded: true,
+			wantValue:  -10,
+		},
+		{
+			name:     "leading zero invalid digits",
+			flag:     IntFlag{Name: "count"},
+			argFound: true,
+			argVal:   s("09a"),
+			wantErr:  true,
+		},
+		{
+			name:       "underscores",
+			flag:       IntFlag{Name: "count"},
+			argFound:   true,
+			argVal:     s("1_000_000"),
+			wantLoaded: true,
+			wantValue:  1000000,
+		},
+		{
+			name:     "missing value",
+			flag:     IntFlag{Name: "count"},
+			argFound: true,
+			wantErr:  true,
+		},
+		{
+			name:     "invalid",
+			flag:     IntFlag{Name: "count"},
+			argFound: true,
+			argVal:   s("five"),
+			wantErr:  true,
+		},
+		{
+			name:     "below min",
+			flag:     IntFlag{Name: "count", Min: intPtr(1)},
+			argFound: true,
+			argVal:   s("0"),
+			wantErr:  true,
+		},
+		{
+			name:     "above max",
+			flag:     IntFlag{Name: "count", Min: intPtr(1), Max: intPtr(10)},
+			argFound: true,
+			argVal:   s("11"),
+			wantErr:  true,
+		},
+		{
+			name:       "within range",
+			flag:       IntFlag{Name: "count", Min: intPtr(1), Max: intPtr(10)},
+			argFound:   true,
+			argVal:     s("10"),
+			wantLoaded: true,
+			wantValue:  10,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gotLoaded, err := tt.flag.Load(tt.argFound, tt.argVal)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Load() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if tt.wantErr {
+				return
+			}
+			if gotLoaded != tt.wantLoaded {
+				t.Errorf("Load() gotLoaded = %v, want %v", gotLoaded, tt.wantLoaded)
+			}
+			if tt.flag.Value != tt.wantValue {
+				t.Errorf("Load() Value = %v, want %v", tt.flag.Value, tt.wantValue)
+			}
+		})
+	}
+}
+
+func TestInt64Flag_Load(t *testing.T) {
+	tests := []struct {
+		name      string
+		flag      Int64Flag
+		argVal    string
+		wantValue int64
+		wantErr   bool
+	}{
+		{name: "decimal", flag: Int64Flag{Name: "size"}, argVal: "42", wantValue: 42},
+		{name: "large", flag: Int64Flag{Name: "size"}, argVal: "9223372036854775807", wantValue: 9223372036854775807},
+		{name: "out of range", flag: Int64Flag{Name: "size"}, argVal: "9223372036854775808", wantErr: true},
+		{name: "negative", flag: Int64Flag{Name: "size"}, argVal: "-42", wantValue: -42},
+		{name: "leading zero", flag: Int64Flag{Name: "size"}, argVal: "0042", wantValue: 42},
+		{name: "hex", flag: Int64Flag{Name: "size"}, argVal: "0xff", wantValue: 255},
+		{name: "octal", flag: Int64Flag{Name: "size"}, argVal: "0o10", wantValue: 8},
+		{name: "binary", flag: Int64Flag{Name: "size"}, argVal: "0b11", wantValue: 3},
+		{name: "invalid", flag: Int64Flag{Name: "size"}, argVal: "1.5", wantErr: true},
+		{name: "below min", flag: Int64Flag{Name: "size", Min: int64Ptr(1)}, argVal: "0", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			loaded, err := tt.flag.Load(true, &tt.argVal)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Load() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+			if !loaded || tt.flag.Value != tt.wantValue {
+				t.Errorf("Load() = %v, Value = %v, want true, %v", loaded, tt.flag.Value, tt.wantValue)
+			}
+		})
+	}
+}
+
+func Test_trimLeadingZeros(t *testing.T) {
+	tests := []struct {
+		val  string
+		want string
+	}{
+		{"0", "0"},
+		{"00", "0"},
+		{"010", "10"},
+		{"-010", "-10"},
+		{"+007", "+7"},
+		{"0_10", "10"},
+		{"10", "10"},
+		{"0x10", "0x10"},
+		{"0o10", "0o10"},
+		{"0b10", "0b10"},
+		{"0X1F", "0X1F"},
+		{"", ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.val, func(t *testing.T) {
+			if got := trimLeadingZeros(tt.val); got != tt.want {
+				t.Errorf("trimLeadingZeros() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestIntFlag_envVar(t *testing.T) {
+	env := map[string]string{"COUNT": "7"}
+
+	flag := &IntFlag{Name: "count", EnvVar: "COUNT"}
+	if err := runTestCmd(nil, env, flag); err != nil || flag.Value != 7 {
+		t.Errorf("run() = %v, Value = %v, want nil, 7", err, flag.Value)
+	}
+
+	// args take precedence over the env var
+	if err := runTestCmd([]string{"--count=8"}, env, flag); err != nil || flag.Value != 8 {
+		t.Errorf("run() = %v, Value = %v, want nil, 8", err, flag.Value)
+	}
+
+	if err := runTestCmd(nil, map[string]string{"COUNT": "seven"}, flag); err == nil {
+		t.Errorf("run() expected error for invalid env var")
+	}
+}
+
+func TestUintFlag_Load(t *testing.T) {
+	flag := UintFlag{Name: "n"}
+
+	if _, err := flag.Load(true, s("-1")); err == nil {
+		t.Errorf("Load() expected error for negative value")
+	}
+
+	loaded, err := flag.Load(true, s("0xff"))
+	if err != nil || !loaded || flag.Value != 255 {
+		t.Errorf("Load() = %v, %v, Value = %v, want true, nil, 255", loaded, err, flag.Value)
+	}
+
+	loaded, err = flag.Load(true, s("010"))
+	if err != nil || !loaded || flag.Value != 10 {
+		t.Errorf("Load() = %v, %v, Value = %v, want true, nil, 10", loaded, err, flag.Value)
+	}
+}
@@ -0,0 +1,134 @@
+package cli
+
+import (
+	"fmt"
+	"net/netip"
+)
+
+// IPFlag is a flag for IPv4 or IPv6 addresses.
+// The value can be provided by cli args or env var.
+// CLI args take precedence.
+type IPFlag struct {
+	Name        string
+	Alias       string
+	EnvVar      string
+	EnvVars     []string // fallback env vars, checked in order if EnvVar is not set
+	Description string
+	Validate    func(value netip.Addr) error // see ValidatedFlag
+	Value       netip.Addr                   // can provide a default value here
+}
+
+func (flag *IPFlag) GetName() string {
+	return flag.Name
+}
+
+func (flag *IPFlag) GetAlias() string {
+	return flag.Alias
+}
+
+func (flag *IPFlag) GetEnvVars() []string {
+	return envVarList(flag.EnvVar, flag.EnvVars)
+}
+
+func (flag *IPFlag) GetDescription() string {
+	desc := flag.Description
+
+	desc = addEnvVarDesc(desc, flag.GetEnvVars())
+
+	return desc
+}
+
+func (flag *IPFlag) GetDefault() string {
+	if !flag.Value.IsValid() {
+		return ""
+	}
+
+	return flag.Value.String()
+}
+
+func (flag *IPFlag) GetPlaceholder() string {
+	return "ip"
+}
+
+func (flag *IPFlag) Load(argFound bool, argVal *string) (loaded bool, err error) {
+	val, found, err := flagValue(flag.Name, argFound, argVal)
+	if !found || err != nil {
+		return found, err
+	}
+
+	addr, err := netip.ParseAddr(val)
+	if err != nil {
+		return false, fmt.Errorf("'%s' is not a valid ip address", val)
+	}
+
+	flag.Value = addr
+	return true, nil
+}
+
+func (flag *IPFlag) ValidateValue() error {
+	return runValidate(flag.Validate, flag.Value)
+}
+
+// CIDRFlag is a flag for network ranges in CIDR notation, eg: 10.0.0.0/8.
+// The value can be provided by cli args or env var.
+// CLI args take precedence.
+type CIDRFlag struct {
+	Name        string
+	Alias       string
+	EnvVar      string
+	EnvVars     []string // fallback env vars, checked in order if EnvVar is not set
+	Description string
+	Validate    func(value netip.Prefix) error // see ValidatedFlag
+	Value       netip.Prefix                   // can provide a default value here
+}
+
+func (flag *CIDRFlag) GetName() string {
+	return flag.Name
+}
+
+func (flag *CIDRFlag) GetAlias() string {
+	return flag.Alias
+}
+
+func (flag *CIDRFlag) GetEnvVars() []string {
+	return envVarList(flag.EnvVar, flag.EnvVars)
+}
+
+func (flag *CIDRFlag) GetDescription() string {
+	desc := flag.Description
+
+	desc = addEnvVarDesc(desc, flag.GetEnvVars())
+
+	return desc
+}
+
+func (flag *CIDRFlag) GetDefault() string {
+	if !flag.Value.IsValid() {
+		return ""
+	}
+
+	return flag.Value.String()
+}
+
+func (flag *CIDRFlag) GetPlaceholder() string {
+	return "cidr"
+}
+
+func (flag *CIDRFlag) Load(argFound bool, argVal *string) (loaded bool, err error) {
+	val, found, err := flagValue(flag.Name, argFound, argVal)
+	if !found || err != nil {
+		return found, err
+	}
+
+	prefix, err := netip.ParsePrefix(val)
+	if err != nil {
+		return false, fmt.Errorf("'%s' is not a valid cidr", val)
+	}
+
+	flag.Value = prefix
+	return true, nil
+}
+
+func (flag *CIDRFlag) ValidateValue() error {
+	return runValidate(flag.Validate, flag.Value)
+}
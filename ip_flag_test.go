@@ -0,0 +1,66 @@
+package cli
+
+import (
+	"testing"
+)
+
+func TestIPFlag_Load(t *testing.T) {
+	tests := []struct {
+		in      string
+		want    string
+		wantErr bool
+	}{
+		{in: "127.0.0.1", want: "127.0.0.1"},
+		{in: "::1", want: "::1"},
+		{in: "2001:DB8::1", want: "2001:db8::1"},
+		{in: "fe80::1%eth0", want: "fe80::1%eth0"},
+		{in: "256.0.0.1", wantErr: true},
+		{in: "10.0.0.0/8", wantErr: true},
+		{in: "localhost", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.in, func(t *testing.T) {
+			flag := IPFlag{Name: "ip"}
+			_, err := flag.Load(true, &tt.in)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Load() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+			if got := flag.Value.String(); got != tt.want {
+				t.Errorf("Load() Value = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCIDRFlag_Load(t *testing.T) {
+	tests := []struct {
+		in      string
+		want    string
+		wantErr bool
+	}{
+		{in: "10.0.0.0/8", want: "10.0.0.0/8"},
+		{in: "192.168.1.1/24", want: "192.168.1.1/24"},
+		{in: "2001:db8::/32", want: "2001:db8::/32"},
+		{in: "10.0.0.0/33", wantErr: true},
+		{in: "10.0.0.0", wantErr: true},
+		{in: "localhost/8", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.in, func(t *testing.T) {
+			flag := CIDRFlag{Name: "cidr"}
+			_, err := flag.Load(true, &tt.in)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Load() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+			if got := flag.Value.String(); got != tt.want {
+				t.Errorf("Load() Value = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
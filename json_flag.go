@@ -2,19 +2,37 @@ package cli
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
-	"os"
+	"io/fs"
+	"reflect"
+	"strings"
 )
 
 // JSONFlag will parse a json string into Value, of type T.
-// The value can be provided by cli args or env var.
-// CLI args take precedence.
+// The value can be provided by cli args, env var or a file.
+// CLI args take precedence, followed by the env var and then the file.
+// If Merge is set, the json from all sources is deep merged instead, using json merge patch semantics (RFC 7386),
+// in order: the default Value, the file, then each value source from the lowest to the highest precedence,
+// eg: the config file, the env var and the cli args. Eg: a base config from the environment
+// can be partially overridden by args. A null removes a field.
+// The expected shape of the json is derived from T, and shown in the help text and errors.
+// If Strict is set, unknown fields are rejected, and struct fields tagged with `required:"true"` must be provided.
 type JSONFlag[T any] struct {
 	Name        string
 	Alias       string
 	EnvVar      string
+	EnvVars     []string // fallback env vars, checked in order if EnvVar is not set
 	Description string
-	Value       T // can provide a default value here
+	Secret      bool                // if true, the raw json is never included in errors and is redacted by Cmd.run
+	Strict      bool                // if true, unknown fields are rejected and required fields are checked
+	File        string              // path to a file containing json, eg: a mounted config
+	Merge       bool                // if true, the json from all sources is merged, instead of using the first source found
+	Validate    func(value T) error // see ValidatedFlag
+	Value       T                   // can provide a default value here
+
+	raw        []string   // raw json loaded, used for redaction
+	provenance Provenance // set if loaded from File, or merged
 }
 
 func (flag *JSONFlag[T]) GetName() string {
@@ -25,40 +43,258 @@ func (flag *JSONFlag[T]) GetAlias() string {
 	return flag.Alias
 }
 
+func (flag *JSONFlag[T]) GetEnvVars() []string {
+	return envVarList(flag.EnvVar, flag.EnvVars)
+}
+
 func (flag *JSONFlag[T]) GetDescription() string {
 	desc := flag.Description
 
-	if flag.EnvVar != "" {
-		if desc != "" {
-			desc += "\n"
-		}
-		desc += "> env var: " + flag.EnvVar
+	desc = addEnvVarDesc(desc, flag.GetEnvVars())
+
+	if flag.File != "" {
+		desc = addDescLine(desc, "> file: "+flag.File)
+	}
+
+	if flag.Merge {
+		desc = addDescLine(desc, "> sources are merged: default, file, env var, args")
+	}
+
+	if schema := flag.schema(); schema != "" {
+		desc = addDescLine(desc, "> schema: "+schema)
 	}
 
 	return desc
 }
 
+func (flag *JSONFlag[T]) GetDefault() string {
+	if flag.Secret || reflect.ValueOf(&flag.Value).Elem().IsZero() {
+		return ""
+	}
+
+	b, err := json.Marshal(flag.Value)
+	if err != nil {
+		return ""
+	}
+
+	return string(b)
+}
+
+func (flag *JSONFlag[T]) GetPlaceholder() string {
+	return "json"
+}
+
 func (flag *JSONFlag[T]) Load(argFound bool, argVal *string) (loaded bool, err error) {
 	if argFound && argVal == nil {
 		return true, fmt.Errorf("no value found")
 	}
 
-	if argFound && argVal != nil {
-		if err := json.Unmarshal([]byte(*argVal), &flag.Value); err != nil {
-			return false, fmt.Errorf("invalid json: %w, loaded from args: '%s'", err, *argVal)
+	flag.provenance = Provenance{}
+	flag.raw = nil
+
+	if flag.Merge {
+		var values []sourceValue
+		if argFound {
+			values = append(values, sourceValue{Provenance: Provenance{Source: SourceArgs, Key: formatFlag(flag.Name), Raw: *argVal}, val: argVal})
 		}
 
-		return true, nil
+		return flag.loadMerged(values)
 	}
 
-	envVal := os.Getenv(flag.EnvVar)
-	if envVal != "" {
-		if err := json.Unmarshal([]byte(envVal), &flag.Value); err != nil {
-			return false, fmt.Errorf("invalid json: %w, loaded from env: '%s'", err, envVal)
+	if argFound {
+		if err := flag.unmarshal(*argVal, ""); err != nil {
+			return false, err
 		}
 
 		return true, nil
 	}
 
-	return false, nil
+	fileVal, ok, err := flag.readFile()
+	if err != nil || !ok {
+		return false, err
+	}
+
+	if err := flag.unmarshal(fileVal, "file"); err != nil {
+		return false, err
+	}
+
+	flag.provenance = Provenance{Source: SourceFile, Key: flag.File, Raw: fileVal}
+	return true, nil
+}
+
+// mergesValues implements mergeFlag
+func (flag *JSONFlag[T]) mergesValues() bool {
+	return flag.Merge
+}
+
+// loadMerged implements mergeFlag, merging the json from the default Value, File and the values found in each source
+func (flag *JSONFlag[T]) loadMerged(values []sourceValue) (loaded bool, err error) {
+	type layer struct {
+		source Source
+		key    string
+		raw    string
+	}
+	var layers []layer
+
+	fileVal, ok, err := flag.readFile()
+	if err != nil {
+		return false, err
+	}
+	if ok {
+		layers = append(layers, layer{SourceFile, flag.File, fileVal})
+	}
+
+	// values are in order of precedence, the last layer takes precedence
+	for i := len(values) - 1; i >= 0; i-- {
+		v := values[i]
+		if v.val == nil {
+			return false, fmt.Errorf("no value found in %s '%s'", v.Source, v.Key)
+		}
+
+		layers = append(layers, layer{v.Source, v.Key, *v.val})
+	}
+
+	flag.provenance = Provenance{}
+	if len(layers) == 0 {
+		return false, nil
+	}
+
+	var doc any
+	if !reflect.ValueOf(&flag.Value).Elem().IsZero() {
+		b, err := json.Marshal(flag.Value)
+		if err != nil {
+			return false, fmt.Errorf("invalid default value: %w", err)
+		}
+		if err := json.Unmarshal(b, &doc); err != nil {
+			return false, fmt.Errorf("invalid default value: %w", err)
+		}
+	}
+
+	var keys []string
+	for _, l := range layers {
+		flag.raw = append(flag.raw, l.raw)
+
+		var patch any
+		if err := json.Unmarshal([]byte(l.raw), &patch); err != nil {
+			if flag.Secret {
+				return false, fmt.Errorf("invalid json: %w, loaded from %s", err, l.source)
+			}
+
+			return false, fmt.Errorf("invalid json: %w, loaded from %s: '%s'", err, l.source, l.raw)
+		}
+
+		doc = mergePatch(doc, patch)
+		keys = append(keys, l.key)
+	}
+
+	merged, err := json.Marshal(doc)
+	if err != nil {
+		return false, err
+	}
+
+	var zero T
+	flag.Value = zero
+	if err := flag.unmarshal(string(merged), "merged sources"); err != nil {
+		return false, err
+	}
+
+	flag.provenance = Provenance{Source: layers[len(layers)-1].source, Key: strings.Join(keys, " + "), Raw: string(merged)}
+	return true, nil
+}
+
+// readFile reads the json from File, if it is set and the file exists
+func (flag *JSONFlag[T]) readFile() (val string, found bool, err error) {
+	if flag.File == "" {
+		return "", false, nil
+	}
+
+	val, err = readValueFile(flag.File)
+	if errors.Is(err, fs.ErrNotExist) {
+		return "", false, nil
+	}
+	if err != nil {
+		return "", false, err
+	}
+
+	return val, val != "", nil
+}
+
+// Provenance implements ProvenanceFlag
+func (flag *JSONFlag[T]) Provenance() Provenance {
+	return flag.provenance
+}
+
+func (flag *JSONFlag[T]) ValidateValue() error {
+	return runValidate(flag.Validate, flag.Value)
+}
+
+// SecretValues implements Secret
+func (flag *JSONFlag[T]) SecretValues() []string {
+	if !flag.Secret {
+		return nil
+	}
+
+	return flag.raw
+}
+
+func (flag *JSONFlag[T]) isSensitive() bool {
+	return flag.Secret
+}
+
+// applySet implements setOverrideFlag
+func (flag *JSONFlag[T]) applySet(path []string, raw string) error {
+	flag.raw = append(flag.raw, raw)
+
+	return setJSONPath(reflect.ValueOf(&flag.Value).Elem(), path, raw)
+}
+
+// unmarshal decodes raw into Value. source describes where raw was loaded from, if not from the value passed to Load.
+func (flag *JSONFlag[T]) unmarshal(raw, source string) error {
+	flag.raw = append(flag.raw, raw)
+
+	if err := flag.decode(raw); err != nil {
+		var expected string
+		if schema := flag.schema(); schema != "" {
+			expected = ", expected: " + schema
+		}
+
+		if source != "" {
+			source = ", loaded from " + source
+		}
+
+		if flag.Secret {
+			return fmt.Errorf("invalid json: %w%s%s", err, source, expected)
+		}
+
+		return fmt.Errorf("invalid json: %w%s: '%s'%s", err, source, raw, expected)
+	}
+
+	return nil
+}
+
+func (flag *JSONFlag[T]) decode(raw string) error {
+	if !flag.Strict {
+		return json.Unmarshal([]byte(raw), &flag.Value)
+	}
+
+	dec := json.NewDecoder(strings.NewReader(raw))
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(&flag.Value); err != nil {
+		return err
+	}
+	if dec.More() {
+		return errors.New("unexpected data after the json value")
+	}
+
+	return checkRequiredJSON(reflect.TypeOf(&flag.Value).Elem(), json.RawMessage(raw), "")
+}
+
+// schema returns the expected shape of the json, or an empty string if T is not a struct, slice or map
+func (flag *JSONFlag[T]) schema() string {
+	t := reflect.TypeOf(&flag.Value).Elem()
+	if !hasJSONSchema(t) {
+		return ""
+	}
+
+	return jsonSchema(t)
 }
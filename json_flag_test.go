@@ -0,0 +1,241 @@
+package cli
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+type testStrictAddress struct {
+	City string `json:"city" required:"true"`
+	Zip  string `json:"zip"`
+}
+
+type testStrictPerson struct {
+	Name      string              `json:"name" required:"true"`
+	Age       int                 `json:"age"`
+	Addresses []testStrictAddress `json:"addresses"`
+}
+
+func TestJSONFlag_Load_strict(t *testing.T) {
+	tests := []struct {
+		name    string
+		strict  bool
+		raw     string
+		wantErr string
+	}{
+		{
+			name: "valid",
+			raw:  `{"name":"gopher","age":13,"addresses":[{"city":"Cape Town"}]}`,
+		},
+		{
+			name:   "valid strict",
+			strict: true,
+			raw:    `{"name":"gopher","age":13,"addresses":[{"city":"Cape Town"}]}`,
+		},
+		{
+			name: "unknown field is ignored",
+			raw:  `{"name":"gopher","agee":13}`,
+		},
+		{
+			name:    "unknown field",
+			strict:  true,
+			raw:     `{"name":"gopher","agee":13}`,
+			wantErr: `unknown field "agee"`,
+		},
+		{
+			name:    "missing required field",
+			strict:  true,
+			raw:     `{"age":13}`,
+			wantErr: "missing required field 'name'",
+		},
+		{
+			name:    "missing nested required field",
+			strict:  true,
+			raw:     `{"name":"gopher","addresses":[{"city":"Cape Town"},{"zip":"8001"}]}`,
+			wantErr: "missing required field 'addresses.1.city'",
+		},
+		{
+			name:    "trailing data",
+			strict:  true,
+			raw:     `{"name":"gopher"} {}`,
+			wantErr: "unexpected data",
+		},
+		{
+			name:    "schema in error",
+			raw:     `{"name":1}`,
+			wantErr: `expected: {"name": string, "age"?: int, "addresses"?: [{"city": string, "zip"?: string}]}`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			flag := JSONFlag[testStrictPerson]{Name: "person", Strict: tt.strict}
+
+			_, err := flag.Load(true, &tt.raw)
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Errorf("Load() unexpected error = %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("Load() error = %v, want %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+type TestSchemaMeta struct {
+	Owner string `json:"owner" required:"true"`
+}
+
+func Test_jsonSchema(t *testing.T) {
+	type embedded struct {
+		ID string `json:"id"`
+	}
+	type node struct {
+		embedded
+		*TestSchemaMeta
+		Value    float64           `json:"value"`
+		Data     []byte            `json:"data"`
+		Hash     [2]byte           `json:"hash"`
+		Extra    any               `json:"extra"`
+		Labels   map[string]string `json:"labels,omitempty"`
+		Children []*node           `json:"children"`
+		Ignored  string            `json:"-"`
+		NoTag    bool
+	}
+
+	want := `{"id"?: string, "owner": string, "value"?: number, "data"?: string, "hash"?: [int], "extra"?: any, "labels"?: {string: string}, "children"?: [{...}], "NoTag"?: bool}`
+	if got := jsonSchema(reflect.TypeOf(node{})); got != want {
+		t.Errorf("jsonSchema() = %v, want %v", got, want)
+	}
+
+	if err := checkRequiredJSON(reflect.TypeOf(node{}), []byte(`{"id":"a"}`), ""); err == nil || !strings.Contains(err.Error(), "'owner'") {
+		t.Errorf("checkRequiredJSON() error = %v, want missing owner", err)
+	}
+	if err := checkRequiredJSON(reflect.TypeOf(node{}), []byte(`{"owner":"gopher"}`), ""); err != nil {
+		t.Errorf("checkRequiredJSON() unexpected error = %v", err)
+	}
+}
+
+func Test_mergePatch(t *testing.T) {
+	tests := []struct {
+		name   string
+		target any
+		patch  any
+		want   any
+	}{
+		{
+			name:   "merge objects",
+			target: map[string]any{"a": "b", "c": map[string]any{"d": "e", "f": "g"}},
+			patch:  map[string]any{"a": "z", "c": map[string]any{"f": nil}},
+			want:   map[string]any{"a": "z", "c": map[string]any{"d": "e"}},
+		},
+		{
+			name:   "replace array",
+			target: map[string]any{"a": []any{"b"}},
+			patch:  map[string]any{"a": []any{"c"}},
+			want:   map[string]any{"a": []any{"c"}},
+		},
+		{
+			name:   "nil target",
+			target: nil,
+			patch:  map[string]any{"a": map[string]any{"b": "c"}},
+			want:   map[string]any{"a": map[string]any{"b": "c"}},
+		},
+		{
+			name:   "non-object patch",
+			target: map[string]any{"a": "b"},
+			patch:  "c",
+			want:   "c",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := mergePatch(tt.target, tt.patch); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("mergePatch() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestJSONFlag_Load_merge(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "person.json")
+	if err := os.WriteFile(path, []byte(`{"addresses":[{"city":"Berlin"}]}`), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	env := map[string]string{"TEST_MERGE_PERSON": `{"name":"env","age":30,"addresses":[{"city":"Cape Town","zip":"8001"}]}`}
+
+	flag := JSONFlag[testStrictPerson]{
+		Name:   "person",
+		EnvVar: "TEST_MERGE_PERSON",
+		File:   path,
+		Merge:  true,
+		Strict: true,
+		Value:  testStrictPerson{Name: "default", Age: 1},
+	}
+
+	if err := runTestCmd([]string{`--person={"age":31}`}, env, &flag); err != nil {
+		t.Fatalf("run() unexpected error = %v", err)
+	}
+
+	want := testStrictPerson{Name: "env", Age: 31, Addresses: []testStrictAddress{{City: "Cape Town", Zip: "8001"}}}
+	if !reflect.DeepEqual(flag.Value, want) {
+		t.Errorf("run() Value = %+v, want %+v", flag.Value, want)
+	}
+
+	wantProv := Provenance{Source: SourceArgs, Key: path + " + TEST_MERGE_PERSON + --person", Raw: `{"addresses":[{"city":"Cape Town","zip":"8001"}],"age":31,"name":"env"}`}
+	if got := flag.Provenance(); got != wantProv {
+		t.Errorf("Provenance() = %+v, want %+v", got, wantProv)
+	}
+
+	// without env vars, the file is merged with the args
+	flag.Value = testStrictPerson{Name: "default", Age: 1}
+	if err := runTestCmd([]string{`--person={"age":31}`}, map[string]string{}, &flag); err != nil {
+		t.Fatalf("run() unexpected error = %v", err)
+	}
+
+	want = testStrictPerson{Name: "default", Age: 31, Addresses: []testStrictAddress{{City: "Berlin"}}}
+	if !reflect.DeepEqual(flag.Value, want) {
+		t.Errorf("run() Value = %+v, want %+v", flag.Value, want)
+	}
+
+	// strict checks apply to the merged json
+	arg := `{"name":null}`
+	if _, err := flag.Load(true, &arg); err == nil || !strings.Contains(err.Error(), "missing required field 'name'") {
+		t.Errorf("Load() error = %v, want missing required field", err)
+	}
+}
+
+func TestJSONFlag_Load_envOverridesFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "server.json")
+	if err := os.WriteFile(path, []byte(`{"host":"file","port":1}`), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	env := map[string]string{"TEST_SERVER": `{"host":"env"}`}
+
+	tests := []struct {
+		name  string
+		merge bool
+		want  testSetServer
+	}{
+		{name: "first source", want: testSetServer{Host: "env"}},
+		{name: "merged", merge: true, want: testSetServer{Host: "env", Port: 1}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			flag := JSONFlag[testSetServer]{Name: "server", EnvVar: "TEST_SERVER", File: path, Merge: tt.merge}
+
+			if err := runTestCmd(nil, env, &flag); err != nil {
+				t.Fatalf("run() unexpected error = %v", err)
+			}
+			if flag.Value != tt.want {
+				t.Errorf("run() Value = %+v, want %+v", flag.Value, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,26 @@
+package cli
+
+// mergePatch applies a json merge patch (RFC 7386) to a decoded json document:
+// objects are merged recursively, null removes a field, and any other value replaces the target.
+func mergePatch(target, patch any) any {
+	patchObj, ok := patch.(map[string]any)
+	if !ok {
+		return patch
+	}
+
+	targetObj, ok := target.(map[string]any)
+	if !ok {
+		targetObj = make(map[string]any)
+	}
+
+	for k, v := range patchObj {
+		if v == nil {
+			delete(targetObj, k)
+			continue
+		}
+
+		targetObj[k] = mergePatch(targetObj[k], v)
+	}
+
+	return targetObj
+}
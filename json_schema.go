@@ -0,0 +1,250 @@
+package cli
+
+import (
+	"encoding"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// jsonFieldInfo describes a struct field, as it is encoded by encoding/json
+type jsonFieldInfo struct {
+	name     string
+	index    []int
+	typ      reflect.Type
+	required bool // tagged with `required:"true"`, see JSONFlag.Strict
+}
+
+// jsonFields returns the fields of a struct type that are encoded by encoding/json,
+// including the promoted fields of embedded structs and pointers to structs.
+// index may pass through embedded pointers, see jsonField.
+func jsonFields(t reflect.Type) []jsonFieldInfo {
+	var fields []jsonFieldInfo
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+
+		tag := field.Tag.Get("json")
+		if tag == "-" {
+			continue
+		}
+
+		name, _, _ := strings.Cut(tag, ",")
+		embedded := field.Type
+		if embedded.Kind() == reflect.Pointer {
+			embedded = embedded.Elem()
+		}
+		if name == "" && field.Anonymous && embedded.Kind() == reflect.Struct {
+			for _, f := range jsonFields(embedded) {
+				f.index = append([]int{i}, f.index...)
+				fields = append(fields, f)
+			}
+			continue
+		}
+
+		if !field.IsExported() {
+			continue
+		}
+
+		if name == "" {
+			name = field.Name
+		}
+
+		fields = append(fields, jsonFieldInfo{
+			name:     name,
+			index:    []int{i},
+			typ:      field.Type,
+			required: field.Tag.Get("required") == "true",
+		})
+	}
+
+	return fields
+}
+
+var (
+	jsonMarshalerType = reflect.TypeOf((*json.Marshaler)(nil)).Elem()
+	textMarshalerType = reflect.TypeOf((*encoding.TextMarshaler)(nil)).Elem()
+)
+
+// jsonSchema returns a compact description of the json expected for a type, eg:
+//
+//	{"name": string, "age": int, "tags": [string], "labels": {string: string}}
+//
+// If any fields of a struct are required (see JSONFlag.Strict), the other fields are marked optional with a ?
+func jsonSchema(t reflect.Type) string {
+	var b strings.Builder
+	writeJSONSchema(&b, t, map[reflect.Type]bool{})
+	return b.String()
+}
+
+func writeJSONSchema(b *strings.Builder, t reflect.Type, seen map[reflect.Type]bool) {
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+
+	switch {
+	case t == rawMessageType || t.Kind() == reflect.Interface:
+		b.WriteString("any")
+		return
+	case t.Implements(jsonMarshalerType) || reflect.PointerTo(t).Implements(jsonMarshalerType):
+		b.WriteString("any")
+		return
+	case t.Implements(textMarshalerType) || reflect.PointerTo(t).Implements(textMarshalerType):
+		b.WriteString("string")
+		return
+	}
+
+	switch t.Kind() {
+	case reflect.String:
+		b.WriteString("string")
+	case reflect.Bool:
+		b.WriteString("bool")
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		b.WriteString("int")
+	case reflect.Float32, reflect.Float64:
+		b.WriteString("number")
+
+	case reflect.Slice, reflect.Array:
+		// []byte is encoded as a base64 string, [N]byte as an array
+		if t.Kind() == reflect.Slice && t.Elem().Kind() == reflect.Uint8 {
+			b.WriteString("string")
+			return
+		}
+
+		b.WriteString("[")
+		writeJSONSchema(b, t.Elem(), seen)
+		b.WriteString("]")
+
+	case reflect.Map:
+		b.WriteString("{string: ")
+		writeJSONSchema(b, t.Elem(), seen)
+		b.WriteString("}")
+
+	case reflect.Struct:
+		// recursive types
+		if seen[t] {
+			b.WriteString("{...}")
+			return
+		}
+		seen[t] = true
+		defer delete(seen, t)
+
+		fields := jsonFields(t)
+		hasRequired := false
+		for _, f := range fields {
+			hasRequired = hasRequired || f.required
+		}
+
+		b.WriteString("{")
+		for i, f := range fields {
+			if i > 0 {
+				b.WriteString(", ")
+			}
+
+			b.WriteString(`"` + f.name + `"`)
+			if hasRequired && !f.required {
+				b.WriteString("?")
+			}
+			b.WriteString(": ")
+			writeJSONSchema(b, f.typ, seen)
+		}
+		b.WriteString("}")
+
+	default:
+		b.WriteString("any")
+	}
+}
+
+// hasJSONSchema returns true if the schema of a type is worth documenting, ie: it is not a single primitive
+func hasJSONSchema(t reflect.Type) bool {
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+
+	switch t.Kind() {
+	case reflect.Struct, reflect.Map, reflect.Slice, reflect.Array:
+		return t != rawMessageType
+	}
+
+	return false
+}
+
+// checkRequiredJSON returns an error if any of the fields of t tagged with `required:"true"` are missing from the json.
+// Nested structs, and structs in slices and maps are also checked.
+func checkRequiredJSON(t reflect.Type, raw json.RawMessage, path string) error {
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+
+	if len(raw) == 0 || string(raw) == "null" {
+		return nil
+	}
+
+	switch t.Kind() {
+	case reflect.Struct:
+		if t.Implements(jsonMarshalerType) || reflect.PointerTo(t).Implements(jsonMarshalerType) {
+			return nil
+		}
+
+		var obj map[string]json.RawMessage
+		if err := json.Unmarshal(raw, &obj); err != nil {
+			return nil // type errors are reported by the decoder
+		}
+
+		for _, f := range jsonFields(t) {
+			val, ok := lookupJSONKey(obj, f.name)
+			if !ok {
+				if f.required {
+					return fmt.Errorf("missing required field '%s'", path+f.name)
+				}
+				continue
+			}
+
+			if err := checkRequiredJSON(f.typ, val, path+f.name+"."); err != nil {
+				return err
+			}
+		}
+
+	case reflect.Slice, reflect.Array:
+		var items []json.RawMessage
+		if err := json.Unmarshal(raw, &items); err != nil {
+			return nil
+		}
+
+		for i, item := range items {
+			if err := checkRequiredJSON(t.Elem(), item, fmt.Sprintf("%s%d.", path, i)); err != nil {
+				return err
+			}
+		}
+
+	case reflect.Map:
+		var obj map[string]json.RawMessage
+		if err := json.Unmarshal(raw, &obj); err != nil {
+			return nil
+		}
+
+		for k, val := range obj {
+			if err := checkRequiredJSON(t.Elem(), val, path+k+"."); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+// lookupJSONKey finds a key in a json object, the same way as encoding/json (an exact match, or case-insensitive)
+func lookupJSONKey(obj map[string]json.RawMessage, name string) (json.RawMessage, bool) {
+	if val, ok := obj[name]; ok {
+		return val, true
+	}
+
+	for k, val := range obj {
+		if strings.EqualFold(k, name) {
+			return val, true
+		}
+	}
+
+	return nil, false
+}
@@ -0,0 +1,261 @@
+package cli
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+// setFlag overrides fields of JSONFlag values, eg: --set=gcp-creds.project_id=my-project
+// It is accepted by every command that has a JSONFlag, unless the command has its own flag named set,
+// and can be repeated.
+var setFlag = StringFlag{
+	Name:        "set",
+	Description: "Override a field of a JSON flag, eg: --set=flag.path.to.field=value (can be repeated)",
+}
+
+// setOverrideFlag is implemented by flags that support --set overrides, eg: JSONFlag
+type setOverrideFlag interface {
+	applySet(path []string, raw string) error
+}
+
+// setOverride is a --set override, eg: --set=flag.a.b=value => {"flag", "a.b", "value"}
+type setOverride struct {
+	flag  string
+	field string
+	raw   string
+}
+
+// parseSetArgs returns the --set overrides in args, in order
+func parseSetArgs(args []string) (overrides []setOverride, err error) {
+	for _, arg := range args {
+		found, val, err := loadFlagFromArgsFormatted(formatFlag(setFlag.Name), []string{arg})
+		if err != nil {
+			return nil, err
+		}
+		if !found {
+			continue
+		}
+		if val == nil {
+			return nil, errors.New("invalid --set: no value provided, expected --set=flag.path=value")
+		}
+
+		path, raw, ok := strings.Cut(*val, "=")
+		if !ok {
+			return nil, fmt.Errorf("invalid --set '%s', expected flag.path=value", *val)
+		}
+
+		name, field, _ := strings.Cut(path, ".")
+		overrides = append(overrides, setOverride{flag: name, field: field, raw: raw})
+	}
+
+	return overrides, nil
+}
+
+// checkSetOverrides returns an error if any of the overrides don't match a flag that supports --set,
+// eg: a misspelled flag name
+func checkSetOverrides(overrides []setOverride, flags ...[]Flag) error {
+	var names []string
+	known := make(map[string]bool)
+	for _, fls := range flags {
+		for _, fl := range fls {
+			if _, ok := flagAs[setOverrideFlag](fl); ok {
+				names = append(names, fl.GetName())
+				known[fl.GetName()] = true
+			}
+		}
+	}
+
+	for _, o := range overrides {
+		if known[o.flag] {
+			continue
+		}
+
+		err := fmt.Sprintf("invalid --set: unknown JSON flag '%s'", o.flag)
+		if suggestions := suggest(o.flag, names, false); len(suggestions) > 0 {
+			err += fmt.Sprintf(", did you mean '%s'?", suggestions[0])
+		}
+		return errors.New(err)
+	}
+
+	return nil
+}
+
+// hasSetOverrideFlags returns true if any of the flags support --set overrides,
+// and none of them is named set, ie: --set is not used by the command itself
+func hasSetOverrideFlags(flags ...[]Flag) bool {
+	supported := false
+	for _, fls := range flags {
+		for _, fl := range fls {
+			if fl.GetName() == setFlag.Name {
+				return false
+			}
+			if _, ok := flagAs[setOverrideFlag](fl); ok {
+				supported = true
+			}
+		}
+	}
+
+	return supported
+}
+
+// setJSONPath sets the field at path in v, converting raw to the type of the field.
+// Struct fields are matched by their json name. Map keys and slice indexes are also supported,
+// eg: servers.0.host. Strings are used as is, other types are parsed as json.
+// json.RawMessage values are decoded, updated and encoded again, eg: for flags created by Bind.
+func setJSONPath(v reflect.Value, path []string, raw string) error {
+	if v.Type() == rawMessageType {
+		return setRawJSONPath(v, path, raw)
+	}
+
+	if len(path) == 0 {
+		return setJSONLeaf(v, raw)
+	}
+
+	key := path[0]
+
+	switch v.Kind() {
+	case reflect.Pointer:
+		if v.IsNil() {
+			v.Set(reflect.New(v.Type().Elem()))
+		}
+		return setJSONPath(v.Elem(), path, raw)
+
+	case reflect.Interface:
+		// eg: any, holding a map[string]any
+		if v.IsNil() {
+			v.Set(reflect.ValueOf(map[string]any{}))
+		}
+		elem := reflect.New(v.Elem().Type()).Elem()
+		elem.Set(v.Elem())
+		if err := setJSONPath(elem, path, raw); err != nil {
+			return err
+		}
+		v.Set(elem)
+		return nil
+
+	case reflect.Struct:
+		field, ok := jsonField(v, key)
+		if !ok {
+			return fmt.Errorf("unknown field '%s'", key)
+		}
+		return setJSONPath(field, path[1:], raw)
+
+	case reflect.Map:
+		if v.Type().Key().Kind() != reflect.String {
+			return fmt.Errorf("unsupported map key type %s", v.Type().Key())
+		}
+		if v.IsNil() {
+			v.Set(reflect.MakeMap(v.Type()))
+		}
+
+		mapKey := reflect.ValueOf(key).Convert(v.Type().Key())
+		elem := reflect.New(v.Type().Elem()).Elem()
+		if existing := v.MapIndex(mapKey); existing.IsValid() {
+			elem.Set(existing)
+		}
+		if err := setJSONPath(elem, path[1:], raw); err != nil {
+			return err
+		}
+		v.SetMapIndex(mapKey, elem)
+		return nil
+
+	case reflect.Slice:
+		i, err := strconv.Atoi(key)
+		if err != nil || i < 0 || i > v.Len() {
+			return fmt.Errorf("invalid index '%s', expected 0 to %d", key, v.Len())
+		}
+		if i == v.Len() {
+			v.Set(reflect.Append(v, reflect.Zero(v.Type().Elem())))
+		}
+		return setJSONPath(v.Index(i), path[1:], raw)
+	}
+
+	return fmt.Errorf("unknown field '%s', %s has no fields", key, v.Type())
+}
+
+var rawMessageType = reflect.TypeOf(json.RawMessage(nil))
+
+// setRawJSONPath sets the field at path in a json.RawMessage, as if it was decoded into an any
+func setRawJSONPath(v reflect.Value, path []string, raw string) error {
+	var val any
+	if msg := v.Bytes(); len(msg) > 0 {
+		if err := json.Unmarshal(msg, &val); err != nil {
+			return err
+		}
+	}
+
+	if err := setJSONPath(reflect.ValueOf(&val).Elem(), path, raw); err != nil {
+		return err
+	}
+
+	b, err := json.Marshal(val)
+	if err != nil {
+		return err
+	}
+
+	v.SetBytes(b)
+	return nil
+}
+
+// setJSONLeaf sets v to raw, converted to the type of v
+func setJSONLeaf(v reflect.Value, raw string) error {
+	switch v.Kind() {
+	case reflect.String:
+		v.SetString(raw)
+		return nil
+
+	case reflect.Interface:
+		// json values are decoded, anything else is used as a string
+		var val any
+		if err := json.Unmarshal([]byte(raw), &val); err != nil {
+			val = raw
+		}
+		if val == nil {
+			v.Set(reflect.Zero(v.Type()))
+			return nil
+		}
+		v.Set(reflect.ValueOf(val))
+		return nil
+	}
+
+	ptr := reflect.New(v.Type())
+	if err := json.Unmarshal([]byte(raw), ptr.Interface()); err != nil {
+		return fmt.Errorf("'%s' is not a valid %s", raw, v.Type())
+	}
+	v.Set(ptr.Elem())
+	return nil
+}
+
+// jsonField finds the struct field with the json name, the same way as encoding/json (case-insensitive)
+func jsonField(v reflect.Value, name string) (reflect.Value, bool) {
+	for _, f := range jsonFields(v.Type()) {
+		if strings.EqualFold(f.name, name) {
+			return jsonFieldByIndex(v, f.index)
+		}
+	}
+
+	return reflect.Value{}, false
+}
+
+// jsonFieldByIndex returns the nested field of v, allocating nil embedded pointers on the way, like encoding/json.
+// It returns false if an embedded pointer can't be allocated, ie: a pointer to an unexported struct.
+func jsonFieldByIndex(v reflect.Value, index []int) (reflect.Value, bool) {
+	for i, x := range index {
+		if i > 0 && v.Kind() == reflect.Pointer {
+			if v.IsNil() {
+				if !v.CanSet() {
+					return reflect.Value{}, false
+				}
+				v.Set(reflect.New(v.Type().Elem()))
+			}
+			v = v.Elem()
+		}
+		v = v.Field(x)
+	}
+
+	return v, true
+}
@@ -0,0 +1,257 @@
+package cli
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+type testSetServer struct {
+	Host string `json:"host"`
+	Port int    `json:"port"`
+}
+
+type testSetConfig struct {
+	Name    string            `json:"name"`
+	Debug   bool              `json:"debug"`
+	Ratio   float64           `json:"ratio"`
+	Servers []testSetServer   `json:"servers"`
+	Labels  map[string]string `json:"labels"`
+	Extra   map[string]any    `json:"extra"`
+	Primary *testSetServer    `json:"primary,omitempty"`
+	Ignored string            `json:"-"`
+}
+
+func TestSetJSONPath(t *testing.T) {
+	tests := []struct {
+		name    string
+		path    string
+		raw     string
+		want    testSetConfig
+		wantErr string
+	}{
+		{
+			name: "string",
+			path: "name",
+			raw:  "gopher",
+			want: testSetConfig{Name: "gopher", Servers: []testSetServer{{Host: "a", Port: 1}}},
+		},
+		{
+			name: "case-insensitive",
+			path: "NAME",
+			raw:  "gopher",
+			want: testSetConfig{Name: "gopher", Servers: []testSetServer{{Host: "a", Port: 1}}},
+		},
+		{
+			name: "bool and float",
+			path: "debug",
+			raw:  "true",
+			want: testSetConfig{Debug: true, Servers: []testSetServer{{Host: "a", Port: 1}}},
+		},
+		{
+			name: "slice index",
+			path: "servers.0.port",
+			raw:  "8080",
+			want: testSetConfig{Servers: []testSetServer{{Host: "a", Port: 8080}}},
+		},
+		{
+			name: "slice append",
+			path: "servers.1.host",
+			raw:  "b",
+			want: testSetConfig{Servers: []testSetServer{{Host: "a", Port: 1}, {Host: "b"}}},
+		},
+		{
+			name: "map",
+			path: "labels.env",
+			raw:  "prod",
+			want: testSetConfig{Servers: []testSetServer{{Host: "a", Port: 1}}, Labels: map[string]string{"env": "prod"}},
+		},
+		{
+			name: "map of any",
+			path: "extra.a.b",
+			raw:  "[1]",
+			want: testSetConfig{Servers: []testSetServer{{Host: "a", Port: 1}}, Extra: map[string]any{"a": map[string]any{"b": []any{1.0}}}},
+		},
+		{
+			name: "pointer",
+			path: "primary.host",
+			raw:  "db",
+			want: testSetConfig{Servers: []testSetServer{{Host: "a", Port: 1}}, Primary: &testSetServer{Host: "db"}},
+		},
+		{
+			name: "object",
+			path: "servers",
+			raw:  `[{"host":"c"}]`,
+			want: testSetConfig{Servers: []testSetServer{{Host: "c"}}},
+		},
+		{
+			name:    "unknown field",
+			path:    "servers.0.hostname",
+			raw:     "b",
+			wantErr: "unknown field 'hostname'",
+		},
+		{
+			name:    "ignored field",
+			path:    "ignored",
+			raw:     "b",
+			wantErr: "unknown field 'ignored'",
+		},
+		{
+			name:    "invalid type",
+			path:    "ratio",
+			raw:     "high",
+			wantErr: "'high' is not a valid float64",
+		},
+		{
+			name:    "invalid index",
+			path:    "servers.5.host",
+			raw:     "b",
+			wantErr: "invalid index '5'",
+		},
+		{
+			name:    "field of a scalar",
+			path:    "name.first",
+			raw:     "b",
+			wantErr: "unknown field 'first'",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := testSetConfig{Servers: []testSetServer{{Host: "a", Port: 1}}}
+
+			err := setJSONPath(reflect.ValueOf(&got).Elem(), strings.Split(tt.path, "."), tt.raw)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Errorf("setJSONPath() error = %v, want %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("setJSONPath() unexpected error = %v", err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("setJSONPath() got = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCmd_run_set(t *testing.T) {
+	t.Setenv("TEST_SET_CONFIG", `{"name":"base","servers":[{"host":"a","port":1}]}`)
+
+	configFlag := &JSONFlag[testSetConfig]{Name: "config", EnvVar: "TEST_SET_CONFIG"}
+	otherFlag := &JSONFlag[testSetServer]{Name: "other"}
+
+	var actionCtx *Context
+	cmd := Cmd{
+		Name:     "test",
+		OptFlags: []Flag{configFlag, otherFlag},
+		ActionCtx: func(ctx *Context) error {
+			actionCtx = ctx
+			return nil
+		},
+	}
+
+	err := cmd.run([]string{"--set=config.servers.0.port=8080", "--set=other.host=b", "--set='config.name=override'"}, []string{"test"})
+	if err != nil {
+		t.Fatalf("run() unexpected error = %v", err)
+	}
+
+	want := testSetConfig{Name: "override", Servers: []testSetServer{{Host: "a", Port: 8080}}}
+	if !reflect.DeepEqual(configFlag.Value, want) {
+		t.Errorf("Value = %+v, want %+v", configFlag.Value, want)
+	}
+	if otherFlag.Value.Host != "b" {
+		t.Errorf("default Value = %+v, want host b", otherFlag.Value)
+	}
+	if got := actionCtx.Provenance(configFlag); got.Key != "TEST_SET_CONFIG --set" {
+		t.Errorf("Provenance() = %+v, want key %q", got, "TEST_SET_CONFIG --set")
+	}
+
+	err = cmd.run([]string{"--set=config.servers.0.hostname=b"}, []string{"test"})
+	if err == nil || !strings.Contains(err.Error(), "--set config.servers.0.hostname: unknown field 'hostname'") {
+		t.Errorf("run() error = %v, want unknown field", err)
+	}
+}
+
+func TestCmd_run_setUnknownFlag(t *testing.T) {
+	tests := []struct {
+		name    string
+		args    []string
+		wantErr string
+	}{
+		{name: "known", args: []string{"--set=server.host=b"}},
+		{name: "misspelled", args: []string{"--set=sever.host=b"}, wantErr: "unknown JSON flag 'sever', did you mean 'server'?"},
+		{name: "not a JSON flag", args: []string{"--set=name=b"}, wantErr: "unknown JSON flag 'name'"},
+		{name: "invalid", args: []string{"--set=server.host"}, wantErr: "expected flag.path=value"},
+		{name: "no value", args: []string{"--set"}, wantErr: "no value provided"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			serverFlag := &JSONFlag[testSetServer]{Name: "server"}
+			nameFlag := &StringFlag{Name: "name"}
+
+			err := runTestCmd(tt.args, map[string]string{}, serverFlag, nameFlag)
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Fatalf("run() unexpected error = %v", err)
+				}
+				if serverFlag.Value.Host != "b" {
+					t.Errorf("Value = %+v, want host b", serverFlag.Value)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("run() error = %v, want %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestApp_RunArgs_userSetFlag(t *testing.T) {
+	tests := []struct {
+		name  string
+		flags []Flag
+	}{
+		{name: "no JSON flags", flags: []Flag{&StringFlag{Name: "set"}}},
+		{name: "with a JSON flag", flags: []Flag{&StringFlag{Name: "set"}, &JSONFlag[testSetServer]{Name: "server"}}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got string
+			app := &App{
+				Name:      "test",
+				OptFlags:  tt.flags,
+				LookupEnv: MapEnv(nil),
+				Action: func(args map[string]string) {
+					got = tt.flags[0].(*StringFlag).Value
+				},
+			}
+
+			if err := app.RunArgs([]string{"--set=foo"}); err != nil {
+				t.Fatalf("RunArgs() unexpected error = %v", err)
+			}
+			if got != "foo" {
+				t.Errorf("Value = %q, want %q", got, "foo")
+			}
+		})
+	}
+}
+
+type TestSetMeta struct {
+	Owner string `json:"owner"`
+}
+
+func TestSetJSONPath_embeddedPointer(t *testing.T) {
+	var v struct {
+		*TestSetMeta
+		Name string `json:"name"`
+	}
+
+	if err := setJSONPath(reflect.ValueOf(&v).Elem(), []string{"owner"}, "gopher"); err != nil {
+		t.Fatalf("setJSONPath() unexpected error = %v", err)
+	}
+	if v.TestSetMeta == nil || v.Owner != "gopher" {
+		t.Errorf("setJSONPath() = %+v, want owner gopher", v.TestSetMeta)
+	}
+}
@@ -0,0 +1,180 @@
+package cli
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+)
+
+// PathFlag is a flag for file system paths.
+// The value can be provided by cli args or env var.
+// CLI args take precedence.
+// A leading ~ is expanded to the user's home directory and environment variables ($HOME or ${HOME}) are expanded.
+// The path can optionally be made absolute and validated against the file system.
+type PathFlag struct {
+	Name         string
+	Alias        string
+	EnvVar       string
+	EnvVars      []string // fallback env vars, checked in order if EnvVar is not set
+	Description  string
+	Absolute     bool                     // if true, the path is made absolute (relative to the working directory)
+	MustExist    bool                     // if true, the path must exist
+	MustNotExist bool                     // if true, the path must not exist
+	MustBeDir    bool                     // if true, the path must be a directory (if it exists)
+	MustBeFile   bool                     // if true, the path must be a regular file (if it exists)
+	Validate     func(value string) error // see ValidatedFlag
+	Value        string                   // can provide a default value here
+}
+
+func (flag *PathFlag) GetName() string {
+	return flag.Name
+}
+
+func (flag *PathFlag) GetAlias() string {
+	return flag.Alias
+}
+
+func (flag *PathFlag) GetEnvVars() []string {
+	return envVarList(flag.EnvVar, flag.EnvVars)
+}
+
+func (flag *PathFlag) GetDescription() string {
+	desc := flag.Description
+
+	desc = addEnvVarDesc(desc, flag.GetEnvVars())
+
+	switch {
+	case flag.MustExist && flag.MustBeDir:
+		desc = addDescLine(desc, "> must be an existing directory")
+	case flag.MustExist && flag.MustBeFile:
+		desc = addDescLine(desc, "> must be an existing file")
+	case flag.MustExist:
+		desc = addDescLine(desc, "> must exist")
+	case flag.MustNotExist:
+		desc = addDescLine(desc, "> must not exist")
+	case flag.MustBeDir:
+		desc = addDescLine(desc, "> must be a directory")
+	case flag.MustBeFile:
+		desc = addDescLine(desc, "> must be a file")
+	}
+
+	return desc
+}
+
+func (flag *PathFlag) GetDefault() string {
+	return flag.Value
+}
+
+func (flag *PathFlag) GetPlaceholder() string {
+	return "path"
+}
+
+func (flag *PathFlag) Load(argFound bool, argVal *string) (loaded bool, err error) {
+	return flag.loadWith(loadContext{lookupEnv: os.LookupEnv}, argFound, argVal)
+}
+
+// loadWith implements contextLoader, expanding env vars with the env of the app
+func (flag *PathFlag) loadWith(lctx loadContext, argFound bool, argVal *string) (loaded bool, err error) {
+	val, found, err := flagValue(flag.Name, argFound, argVal)
+	if err != nil {
+		return found, err
+	}
+
+	if found {
+		flag.Value = val
+	}
+
+	// the default value is expanded and validated too
+	if flag.Value == "" {
+		return false, nil
+	}
+
+	path, err := expandPath(flag.Value, lctx.lookupEnv)
+	if err != nil {
+		return false, err
+	}
+
+	if flag.Absolute {
+		path, err = filepath.Abs(path)
+		if err != nil {
+			return false, fmt.Errorf("making path absolute: %w", err)
+		}
+	}
+
+	if err := flag.validate(path); err != nil {
+		return false, err
+	}
+
+	flag.Value = path
+	return found, nil
+}
+
+func (flag *PathFlag) ValidateValue() error {
+	return runValidate(flag.Validate, flag.Value)
+}
+
+func (flag *PathFlag) validate(path string) error {
+	info, err := os.Stat(path)
+	if os.IsNotExist(err) {
+		if flag.MustExist {
+			return fmt.Errorf("'%s' does not exist", path)
+		}
+
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("'%s': %w", path, err)
+	}
+
+	if flag.MustNotExist {
+		return fmt.Errorf("'%s' already exists", path)
+	}
+
+	if flag.MustBeDir && !info.IsDir() {
+		return fmt.Errorf("'%s' is not a directory", path)
+	}
+
+	if flag.MustBeFile && !info.Mode().IsRegular() {
+		return fmt.Errorf("'%s' is not a file", path)
+	}
+
+	return nil
+}
+
+// expandPath expands a leading ~ to the user's home directory, and any environment variables
+func expandPath(path string, lookupEnv func(name string) (string, bool)) (string, error) {
+	path = os.Expand(path, func(name string) string {
+		val, _ := lookupEnv(name)
+		return val
+	})
+
+	if path != "~" && !strings.HasPrefix(path, "~/") && !strings.HasPrefix(path, "~"+string(filepath.Separator)) {
+		return path, nil
+	}
+
+	home, err := userHomeDir(lookupEnv)
+	if err != nil {
+		return "", fmt.Errorf("expanding '~': %w", err)
+	}
+
+	return filepath.Join(home, path[1:]), nil
+}
+
+// userHomeDir is like os.UserHomeDir, but reads env vars with lookupEnv
+func userHomeDir(lookupEnv func(name string) (string, bool)) (string, error) {
+	env := "HOME"
+	switch runtime.GOOS {
+	case "windows":
+		env = "USERPROFILE"
+	case "plan9":
+		env = "home"
+	}
+
+	if home, _ := lookupEnv(env); home != "" {
+		return home, nil
+	}
+
+	return "", fmt.Errorf("$%s is not defined", env)
+}
@@ -0,0 +1,75 @@
+package cli
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestPathFlag_Load(t *testing.T) {
+	dir := t.TempDir()
+	file := filepath.Join(dir, "file.txt")
+	if err := os.WriteFile(file, nil, 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	t.Setenv("HOME", dir)
+	t.Setenv("TEST_PATH_FLAG_DIR", dir)
+
+	tests := []struct {
+		name      string
+		flag      PathFlag
+		argVal    string
+		wantValue string
+		wantErr   bool
+	}{
+		{
+			name:      "home",
+			flag:      PathFlag{Name: "path"},
+			argVal:    "~/file.txt",
+			wantValue: file,
+		},
+		{
+			name:      "env var",
+			flag:      PathFlag{Name: "path"},
+			argVal:    "$TEST_PATH_FLAG_DIR/file.txt",
+			wantValue: file,
+		},
+		{
+			name:    "must exist",
+			flag:    PathFlag{Name: "path", MustExist: true},
+			argVal:  "~/missing.txt",
+			wantErr: true,
+		},
+		{
+			name:    "must not exist",
+			flag:    PathFlag{Name: "path", MustNotExist: true},
+			argVal:  "~/file.txt",
+			wantErr: true,
+		},
+		{
+			name:    "must be dir",
+			flag:    PathFlag{Name: "path", MustBeDir: true},
+			argVal:  "~/file.txt",
+			wantErr: true,
+		},
+		{
+			name:    "must be file",
+			flag:    PathFlag{Name: "path", MustBeFile: true},
+			argVal:  "~",
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := tt.flag.Load(true, &tt.argVal)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Load() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !tt.wantErr && tt.flag.Value != tt.wantValue {
+				t.Errorf("Load() Value = %v, want %v", tt.flag.Value, tt.wantValue)
+			}
+		})
+	}
+}
@@ -0,0 +1,297 @@
+package cli
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// profileCmdName is the name of the command added by App.Profiles
+const profileCmdName = "profile"
+
+// profileFlag selects the profile to load flag values from.
+// It is accepted by every command if App.Profiles is enabled.
+func (app *App) profileFlag() *StringFlag {
+	envVar := app.ProfileEnvVar
+	if envVar == "" {
+		prefix := app.EnvPrefix
+		if prefix == "" {
+			prefix = app.Name
+		}
+
+		envVar = envVarName(prefix, "profile")
+	}
+
+	return &StringFlag{
+		Name:        "profile",
+		EnvVar:      envVar,
+		Description: "Name of the profile to load flag values from",
+	}
+}
+
+// profileDir returns the directory containing the profiles of the app
+func (app *App) profileDir() (string, error) {
+	if app.ProfileDir != "" {
+		return expandPath(app.ProfileDir, app.lookupEnv)
+	}
+
+	dir, err := userConfigDir(app.lookupEnv)
+	if err != nil {
+		return "", fmt.Errorf("profile dir: %w", err)
+	}
+
+	return filepath.Join(dir, app.Name, "profiles"), nil
+}
+
+func (app *App) profilePath(name string) (string, error) {
+	if name == "" || name == "." || name == ".." || strings.ContainsAny(name, `/\`) {
+		return "", fmt.Errorf("invalid profile name '%s'", name)
+	}
+
+	dir, err := app.profileDir()
+	if err != nil {
+		return "", err
+	}
+
+	return filepath.Join(dir, name+".json"), nil
+}
+
+// loadProfile loads the values of the named profile. The profile must exist.
+func (app *App) loadProfile(name string) (*configFile, error) {
+	path, err := app.profilePath(name)
+	if err != nil {
+		return nil, err
+	}
+
+	profile, err := readConfigFile(path, app.lookupEnv)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, fmt.Errorf("profile '%s' not found (%s)", name, path)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	profile.source = SourceProfile
+	return profile, nil
+}
+
+// activeProfile returns the name of the profile selected by the --profile flag or env var, if any
+func (app *App) activeProfile(flagArgs []string) (string, error) {
+	fl := app.profileFlag()
+
+	found, val, err := LoadFlagFromArgs(fl.Name, fl.Alias, flagArgs)
+	if err != nil {
+		return "", fmt.Errorf("load flag from args: '%s': %w", fl.Name, err)
+	}
+
+	if !found {
+		_, val, found, err = lookupEnvVars(app.lookupEnv, fl.GetEnvVars())
+		if err != nil {
+			return "", fmt.Errorf("loading flag: '%s': %w", fl.Name, err)
+		}
+	}
+
+	if _, err := fl.Load(found, val); err != nil {
+		return "", fmt.Errorf("loading flag: '%s': %w", fl.Name, err)
+	}
+
+	return fl.Value, nil
+}
+
+// profileCmd manages the profiles of the app
+func profileCmd(app *App) Cmd {
+	return Cmd{
+		Name:        profileCmdName,
+		Description: "Manage named sets of flag values",
+		SubCmds: []Cmd{
+			{
+				Name:        "list",
+				Description: "List profiles, the active profile is marked with *",
+				ActionCtx: func(ctx *Context) error {
+					active, err := app.activeProfile(nil)
+					if err != nil {
+						return err
+					}
+
+					return listProfiles(os.Stdout, app, active)
+				},
+			},
+			{
+				Name:        "show",
+				Description: "Show the values of a profile (defaults to the active profile)",
+				Args:        []string{"name"},
+				ActionCtx: func(ctx *Context) error {
+					name := ctx.Args["name"]
+					if name == "" {
+						active, err := app.activeProfile(nil)
+						if err != nil {
+							return err
+						}
+						name = active
+					}
+
+					return showProfile(os.Stdout, app, name)
+				},
+			},
+			{
+				Name:        "set",
+				Description: "Set a value in a profile, eg: set staging db-conn postgres://staging (the profile is created if needed)",
+				Args:        []string{"name", "key", "value"},
+				ActionCtx: func(ctx *Context) error {
+					return setProfileValue(app, ctx.Args["name"], ctx.Args["key"], ctx.Args["value"])
+				},
+			},
+		},
+	}
+}
+
+// listProfiles writes the names of all profiles
+func listProfiles(w io.Writer, app *App, active string) error {
+	dir, err := app.profileDir()
+	if err != nil {
+		return err
+	}
+
+	entries, err := os.ReadDir(dir)
+	if err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return fmt.Errorf("listing profiles: %w", err)
+	}
+
+	for _, entry := range entries {
+		if entry.IsDir() || !strings.HasSuffix(entry.Name(), ".json") {
+			continue
+		}
+		name := strings.TrimSuffix(entry.Name(), ".json")
+
+		marker := " "
+		if name == active {
+			marker = "*"
+		}
+
+		fmt.Fprintln(w, marker, name)
+	}
+
+	return nil
+}
+
+// showProfile writes the values of a profile, one key per line.
+// Values of sensitive flags, eg: SecretFlag, are redacted. A key is sensitive if any part of it is
+// the name of a sensitive flag, eg: db.password or a field of a secret JSONFlag.
+func showProfile(w io.Writer, app *App, name string) error {
+	if name == "" {
+		return errors.New("no profile specified")
+	}
+
+	profile, err := app.loadProfile(name)
+	if err != nil {
+		return err
+	}
+
+	values := make(map[string]string)
+	if err := flattenConfigValues(values, "", profile.values); err != nil {
+		return fmt.Errorf("profile '%s': %w", name, err)
+	}
+
+	keys := make([]string, 0, len(values))
+	for k := range values {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	sensitive := sensitiveFlagNames(make(map[string]bool), Cmd{ReqFlags: app.ReqFlags, OptFlags: app.OptFlags, SubCmds: app.SubCmds})
+	for _, k := range keys {
+		val := values[k]
+		for _, part := range strings.Split(k, ".") {
+			if sensitive[part] {
+				val = redactedValue
+				break
+			}
+		}
+
+		fmt.Fprintf(w, "%s = %s\n", k, val)
+	}
+
+	return nil
+}
+
+// setProfileValue sets a value in a profile, creating the profile if it doesn't exist.
+// key is the dot separated path to the value, eg: db-conn or db.migrate.steps
+func setProfileValue(app *App, name, key, value string) error {
+	if key == "" {
+		return errors.New("no key specified")
+	}
+
+	path, err := app.profilePath(name)
+	if err != nil {
+		return err
+	}
+
+	values := make(map[string]any)
+	profile, err := readConfigFile(path, app.lookupEnv)
+	if err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return err
+	}
+	if profile != nil && profile.values != nil {
+		values = profile.values
+	}
+
+	keys := strings.Split(key, ".")
+	cur := values
+	for _, k := range keys[:len(keys)-1] {
+		next, ok := cur[k].(map[string]any)
+		if !ok {
+			if _, exists := cur[k]; exists {
+				return fmt.Errorf("profile '%s': key '%s' is not an object", name, k)
+			}
+
+			next = make(map[string]any)
+			cur[k] = next
+		}
+		cur = next
+	}
+	cur[keys[len(keys)-1]] = value
+
+	b, err := json.MarshalIndent(values, "", "    ")
+	if err != nil {
+		return fmt.Errorf("profile '%s': %w", name, err)
+	}
+
+	// profiles can contain secrets, eg: credentials
+	if err := os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+		return fmt.Errorf("creating profile dir: %w", err)
+	}
+
+	if err := os.WriteFile(path, append(b, '\n'), 0o600); err != nil {
+		return fmt.Errorf("writing profile: %w", err)
+	}
+
+	return nil
+}
+
+// flattenConfigValues flattens nested config values into dot separated keys
+func flattenConfigValues(flat map[string]string, prefix string, values map[string]any) error {
+	for k, v := range values {
+		key := prefix + k
+
+		if nested, ok := v.(map[string]any); ok {
+			if err := flattenConfigValues(flat, key+".", nested); err != nil {
+				return err
+			}
+			continue
+		}
+
+		val, err := configValueString(v)
+		if err != nil {
+			return fmt.Errorf("key '%s': %w", key, err)
+		}
+		flat[key] = val
+	}
+
+	return nil
+}
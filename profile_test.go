@@ -0,0 +1,139 @@
+package cli
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestProfiles(t *testing.T) {
+	dir := t.TempDir()
+	app := &App{
+		Name:       "myapp",
+		Profiles:   true,
+		ProfileDir: dir,
+		OptFlags:   []Flag{&SecretFlag{Name: "token"}},
+		SubCmds: []Cmd{{
+			Name:     "db",
+			OptFlags: []Flag{&JSONFlag[map[string]string]{Name: "creds", Secret: true}},
+		}},
+	}
+
+	for _, kv := range [][3]string{
+		{"staging", "db-conn", "postgres://staging"},
+		{"staging", "db.migrate.steps", "3"},
+		{"staging", "token", "hunter2"},
+		{"staging", "db.creds.password", "hunter3"},
+		{"production", "db-conn", "postgres://production"},
+	} {
+		if err := setProfileValue(app, kv[0], kv[1], kv[2]); err != nil {
+			t.Fatalf("setProfileValue() unexpected error = %v", err)
+		}
+	}
+
+	if err := setProfileValue(app, "staging", "db-conn.host", "x"); err == nil {
+		t.Errorf("setProfileValue() expected error for a key nested under a value")
+	}
+	if err := setProfileValue(app, "../staging", "db-conn", "x"); err == nil {
+		t.Errorf("setProfileValue() expected error for an invalid profile name")
+	}
+
+	var buf bytes.Buffer
+	if err := listProfiles(&buf, app, "staging"); err != nil {
+		t.Fatalf("listProfiles() unexpected error = %v", err)
+	}
+	if want := "  production\n* staging\n"; buf.String() != want {
+		t.Errorf("listProfiles() = %q, want %q", buf.String(), want)
+	}
+
+	buf.Reset()
+	if err := showProfile(&buf, app, "staging"); err != nil {
+		t.Fatalf("showProfile() unexpected error = %v", err)
+	}
+	if want := "db-conn = postgres://staging\ndb.creds.password = *****\ndb.migrate.steps = 3\ntoken = *****\n"; buf.String() != want {
+		t.Errorf("showProfile() = %q, want %q", buf.String(), want)
+	}
+
+	info, err := os.Stat(filepath.Join(dir, "staging.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if perm := info.Mode().Perm(); perm != 0o600 {
+		t.Errorf("profile permissions = %v, want %v", perm, os.FileMode(0o600))
+	}
+}
+
+func TestCmd_run_profile(t *testing.T) {
+	dir := t.TempDir()
+	configPath := filepath.Join(dir, "config.json")
+	if err := os.WriteFile(configPath, []byte(`{"db-conn": "postgres://config", "count": 1}`), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	app := &App{Name: "myapp", Profiles: true, ProfileDir: dir, ConfigFile: configPath}
+	if err := setProfileValue(app, "staging", "db-conn", "postgres://staging"); err != nil {
+		t.Fatal(err)
+	}
+
+	var actionCtx *Context
+	newCmd := func() (*Cmd, *StringFlag, *IntFlag) {
+		dbConnFlag := &StringFlag{Name: "db-conn", EnvVar: "TEST_PROFILE_DB_CONN"}
+		countFlag := &IntFlag{Name: "count"}
+
+		return &Cmd{
+			Name:     "myapp",
+			OptFlags: []Flag{dbConnFlag, countFlag},
+			ActionCtx: func(ctx *Context) error {
+				actionCtx = ctx
+				return nil
+			},
+			app: app,
+		}, dbConnFlag, countFlag
+	}
+
+	// profile selected by flag, takes precedence over the config file
+	cmd, dbConnFlag, countFlag := newCmd()
+	if err := cmd.run([]string{"--profile=staging"}, []string{"myapp"}); err != nil {
+		t.Fatalf("run() unexpected error = %v", err)
+	}
+	if dbConnFlag.Value != "postgres://staging" {
+		t.Errorf("Value = %v, want %v", dbConnFlag.Value, "postgres://staging")
+	}
+	if countFlag.Value != 1 {
+		t.Errorf("config Value = %v, want %v", countFlag.Value, 1)
+	}
+	if got := actionCtx.Provenance(dbConnFlag).Source; got != SourceProfile {
+		t.Errorf("Provenance() = %v, want %v", got, SourceProfile)
+	}
+
+	// profile selected by env var, env vars take precedence over the profile
+	t.Setenv("MYAPP_PROFILE", "staging")
+	t.Setenv("TEST_PROFILE_DB_CONN", "postgres://env")
+	cmd, dbConnFlag, _ = newCmd()
+	if err := cmd.run(nil, []string{"myapp"}); err != nil {
+		t.Fatalf("run() unexpected error = %v", err)
+	}
+	if dbConnFlag.Value != "postgres://env" {
+		t.Errorf("Value = %v, want %v", dbConnFlag.Value, "postgres://env")
+	}
+
+	// unknown profile
+	cmd, _, _ = newCmd()
+	err := cmd.run([]string{"--profile=prod"}, []string{"myapp"})
+	if err == nil || !strings.Contains(err.Error(), "profile 'prod' not found") {
+		t.Errorf("run() error = %v, want profile not found", err)
+	}
+}
+
+func TestApp_RunArgs_profileError(t *testing.T) {
+	app := &App{Name: "myapp", Profiles: true, ProfileDir: t.TempDir(), LookupEnv: MapEnv(nil)}
+
+	if err := app.RunArgs([]string{"profile", "show", "missing"}); err == nil {
+		t.Errorf("RunArgs() expected error for a missing profile")
+	}
+	if err := app.RunArgs([]string{"profile", "set", "../staging", "db-conn", "x"}); err == nil {
+		t.Errorf("RunArgs() expected error for an invalid profile name")
+	}
+}
@@ -0,0 +1,130 @@
+package cli
+
+import (
+	"fmt"
+	"io"
+	"text/tabwriter"
+)
+
+// Source describes where the value of a flag was loaded from
+type Source string
+
+const (
+	SourceDefault Source = "default" // the flag was not provided, the default value is used
+	SourceArgs    Source = "args"    // cli args
+	SourceEnv     Source = "env"     // an env var
+	SourceConfig  Source = "config"  // a config file
+	SourceProfile Source = "profile" // a profile, see App.Profiles
+	SourceDotEnv  Source = "dotenv"  // a dotenv file, see DotEnvSource
+	SourceFile    Source = "file"    // a file referenced by the flag, eg: SecretFlag.File
+	SourcePrompt  Source = "prompt"  // an interactive prompt
+	SourceUnknown Source = "unknown" // a custom flag that was loaded from an unknown source
+)
+
+// Provenance describes where the value of a flag was loaded from
+type Provenance struct {
+	Source Source
+	Key    string // the cli arg, env var, config key or file path the value was loaded from
+	Raw    string // the raw value, as provided
+}
+
+// IsSet returns true if the value was provided, ie: the default value is not used
+func (p Provenance) IsSet() bool {
+	return p.Source != "" && p.Source != SourceDefault
+}
+
+// ProvenanceFlag can be implemented by flags that load values from sources other than cli args or env vars,
+// eg: files or prompts. If Provenance returns an empty Source, the provenance is determined by the command.
+type ProvenanceFlag interface {
+	Flag
+
+	Provenance() Provenance
+}
+
+// EnvVarFlag is implemented by flags that can be loaded from env vars.
+// All built-in flag types implement this interface.
+type EnvVarFlag interface {
+	Flag
+
+	// GetEnvVars returns the env vars the flag can be loaded from, in order of precedence.
+	GetEnvVars() []string
+}
+
+// Context is passed to Cmd.ActionCtx, with the positional args and where each flag value was loaded from
+type Context struct {
+	Args map[string]string // positional args, see Cmd.Args
+
+	provenances map[string]Provenance // by flag name, see flagKey
+}
+
+// Provenance returns where the value of a flag was loaded from
+func (ctx *Context) Provenance(fl Flag) Provenance {
+	p, ok := ctx.provenances[flagKey(fl)]
+	if !ok {
+		return Provenance{Source: SourceDefault}
+	}
+
+	return p
+}
+
+// IsSet returns true if the flag was provided (from any source), ie: false if the default value is used
+func (ctx *Context) IsSet(fl Flag) bool {
+	return ctx.Provenance(fl).IsSet()
+}
+
+// flagProvenance determines where a flag was loaded from.
+// p is the provenance reported by the value source the flag was loaded from.
+func flagProvenance(fl Flag, loaded bool, p Provenance) Provenance {
+	if pf, ok := flagAs[ProvenanceFlag](fl); ok {
+		if p := pf.Provenance(); p.Source != "" {
+			return p
+		}
+	}
+
+	if !loaded {
+		p := Provenance{Source: SourceDefault}
+		if df, ok := flagAs[DefaultValueFlag](fl); ok {
+			p.Raw = df.GetDefault()
+		}
+
+		return p
+	}
+
+	return p
+}
+
+// printConfigFlag prints the provenance of all flags instead of running the command
+var printConfigFlag = BoolFlag{
+	Name:        "print-config",
+	Description: `Print where each flag value was loaded from, without running the command`,
+}
+
+// printProvenance writes the provenance of each flag. Secret values and passwords in urls are redacted.
+func printProvenance(w io.Writer, flags []Flag, ctx *Context, secrets []string) {
+	tw := tabwriter.NewWriter(w, 0, 0, 4, ' ', 0)
+	fmt.Fprintln(tw, "FLAG\tSOURCE\tKEY\tVALUE")
+	for _, fl := range flags {
+		p := ctx.Provenance(fl)
+		raw := redactURL(redact(p.Raw, secrets))
+		if s, ok := flagAs[Secret](fl); ok && len(s.SecretValues()) > 0 && raw != "" {
+			raw = redactedValue
+		}
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%s\n", joinFlagNames([]Flag{fl}, ""), p.Source, p.Key, raw)
+	}
+	tw.Flush()
+}
+
+// isPrintConfig returns true if --print-config is provided in flagArgs, eg: --print-config or --print-config=true
+func isPrintConfig(flagArgs []string) (bool, error) {
+	fl := printConfigFlag
+	found, val, err := LoadFlagFromArgs(fl.Name, fl.Alias, flagArgs)
+	if err != nil {
+		return false, err
+	}
+
+	if _, err := fl.Load(found, val); err != nil {
+		return false, fmt.Errorf("loading flag: '%s': %w", fl.Name, err)
+	}
+
+	return fl.Value, nil
+}
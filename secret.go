@@ -0,0 +1,110 @@
+package cli
+
+import (
+	"strings"
+)
+
+const redactedValue = "*****"
+
+// minRedactLength is the length from which secret values are redacted wherever they occur in a text.
+// Shorter values (eg: "1" or "dev") are only redacted as a whole quoted value, eg: '1',
+// since replacing every occurrence would mangle the text.
+const minRedactLength = 6
+
+// Secret can be implemented by flags that hold sensitive values.
+// Any error returned by Cmd.run has these values redacted.
+type Secret interface {
+	// SecretValues returns the values that must never be printed.
+	// This should include any raw input the flag was loaded from.
+	SecretValues() []string
+}
+
+// redactedError is an error with secret values redacted from its message.
+// The original error can still be matched with errors.Is and errors.As.
+type redactedError struct {
+	msg string
+	err error
+}
+
+func (e *redactedError) Error() string {
+	return e.msg
+}
+
+// Unwrap returns the original error
+func (e *redactedError) Unwrap() error {
+	return e.err
+}
+
+// redactErr returns err with the secret values redacted from its message, see redact
+func redactErr(err error, secrets []string) error {
+	if err == nil {
+		return nil
+	}
+
+	msg := redact(err.Error(), secrets)
+	if msg == err.Error() {
+		return err
+	}
+
+	return &redactedError{msg: msg, err: err}
+}
+
+// sensitiveFlag is implemented by flags whose values are always secret, eg: SecretFlag.
+// Unlike Secret, it doesn't require the flag to be loaded, eg: to redact profile values.
+type sensitiveFlag interface {
+	isSensitive() bool
+}
+
+// redact replaces the secret values in s, see minRedactLength
+func redact(s string, secrets []string) string {
+	for _, secret := range secrets {
+		if secret == "" {
+			continue
+		}
+
+		if s == secret {
+			return redactedValue
+		}
+
+		if len(secret) >= minRedactLength {
+			s = strings.ReplaceAll(s, secret, redactedValue)
+			continue
+		}
+
+		s = strings.ReplaceAll(s, "'"+secret+"'", "'"+redactedValue+"'")
+		s = strings.ReplaceAll(s, `"`+secret+`"`, `"`+redactedValue+`"`)
+	}
+
+	return s
+}
+
+// sensitiveFlagNames returns the names and aliases of the sensitive flags of the commands and their sub commands
+func sensitiveFlagNames(names map[string]bool, cmds ...Cmd) map[string]bool {
+	for _, cmd := range cmds {
+		for _, fl := range append(append([]Flag{}, cmd.ReqFlags...), cmd.OptFlags...) {
+			if sf, ok := flagAs[sensitiveFlag](fl); ok && sf.isSensitive() {
+				names[fl.GetName()] = true
+				names[fl.GetAlias()] = true
+			}
+		}
+
+		sensitiveFlagNames(names, cmd.SubCmds...)
+	}
+
+	delete(names, "")
+	return names
+}
+
+// flagSecrets returns the secret values of all flags that implement Secret
+func flagSecrets(flags ...[]Flag) []string {
+	var secrets []string
+	for _, fls := range flags {
+		for _, fl := range fls {
+			if s, ok := flagAs[Secret](fl); ok {
+				secrets = append(secrets, s.SecretValues()...)
+			}
+		}
+	}
+
+	return secrets
+}
@@ -0,0 +1,154 @@
+package cli
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+
+	"golang.org/x/term"
+)
+
+// SecretFlag is a flag for sensitive values, eg: passwords, tokens and keys.
+// The value can be provided by cli args, env var, a file or an interactive prompt.
+// CLI args take precedence, followed by the env var and then the file.
+// If no value is provided and Prompt is set, the user is prompted for the value
+// without echoing the input (only if stdin is a terminal).
+// The value is never included in errors or help output,
+// and is redacted from any error returned by Cmd.run.
+type SecretFlag struct {
+	Name        string
+	Alias       string
+	EnvVar      string
+	EnvVars     []string // fallback env vars, checked in order if EnvVar is not set
+	File        string   // path to a file containing the value, eg: a mounted secret
+	Prompt      string   // if set, the text used to prompt for the value when it was not provided
+	Description string
+	Validate    func(value string) error // see ValidatedFlag
+	Value       string
+
+	provenance Provenance // set if loaded from File or Prompt
+}
+
+func (flag *SecretFlag) GetName() string {
+	return flag.Name
+}
+
+func (flag *SecretFlag) GetAlias() string {
+	return flag.Alias
+}
+
+func (flag *SecretFlag) GetEnvVars() []string {
+	return envVarList(flag.EnvVar, flag.EnvVars)
+}
+
+func (flag *SecretFlag) GetDescription() string {
+	desc := flag.Description
+
+	desc = addEnvVarDesc(desc, flag.GetEnvVars())
+
+	if flag.File != "" {
+		desc = addDescLine(desc, "> file: "+flag.File)
+	}
+
+	if flag.Prompt != "" {
+		desc = addDescLine(desc, "> prompted for if not provided")
+	}
+
+	return desc
+}
+
+func (flag *SecretFlag) GetDefault() string {
+	// never show the value of a secret
+	return ""
+}
+
+func (flag *SecretFlag) GetPlaceholder() string {
+	return "secret"
+}
+
+func (flag *SecretFlag) Load(argFound bool, argVal *string) (loaded bool, err error) {
+	val, found, err := flagValue(flag.Name, argFound, argVal)
+	if err != nil {
+		return found, err
+	}
+
+	flag.provenance = Provenance{}
+
+	if !found && flag.File != "" {
+		val, err = readValueFile(flag.File)
+		if err != nil && !errors.Is(err, fs.ErrNotExist) {
+			return false, err
+		}
+		found = err == nil && val != ""
+
+		if found {
+			flag.provenance = Provenance{Source: SourceFile, Key: flag.File, Raw: val}
+		}
+	}
+
+	if !found && flag.Prompt != "" {
+		val, found, err = promptSecret(flag.Prompt)
+		if err != nil {
+			return false, err
+		}
+
+		if found {
+			flag.provenance = Provenance{Source: SourcePrompt, Raw: val}
+		}
+	}
+
+	if !found {
+		return false, nil
+	}
+
+	flag.Value = val
+	return true, nil
+}
+
+func (flag *SecretFlag) ValidateValue() error {
+	return runValidate(flag.Validate, flag.Value)
+}
+
+// Provenance implements ProvenanceFlag
+func (flag *SecretFlag) Provenance() Provenance {
+	return flag.provenance
+}
+
+// SecretValues implements Secret
+func (flag *SecretFlag) SecretValues() []string {
+	return []string{flag.Value}
+}
+
+func (flag *SecretFlag) isSensitive() bool {
+	return true
+}
+
+// String prevents the value from being printed, eg: in debug logs.
+// It has a value receiver, so that both SecretFlag and *SecretFlag are redacted.
+func (flag SecretFlag) String() string {
+	return redactedValue
+}
+
+// GoString prevents the value from being printed with %#v
+func (flag SecretFlag) GoString() string {
+	return fmt.Sprintf("cli.SecretFlag{Name:%q, Value:%s}", flag.Name, redactedValue)
+}
+
+// promptSecret prompts for a value on the terminal without echoing the input.
+// If stdin is not a terminal, no prompt is shown and found will be false.
+func promptSecret(prompt string) (val string, found bool, err error) {
+	fd := int(os.Stdin.Fd())
+	if !term.IsTerminal(fd) {
+		return "", false, nil
+	}
+
+	fmt.Fprint(os.Stderr, prompt+": ")
+	b, err := term.ReadPassword(fd)
+	fmt.Fprintln(os.Stderr)
+	if err != nil {
+		return "", false, fmt.Errorf("reading from terminal: %w", err)
+	}
+
+	return string(b), len(b) > 0, nil
+}
@@ -0,0 +1,80 @@
+package cli
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+)
+
+func Test_redact(t *testing.T) {
+	tests := []struct {
+		name    string
+		s       string
+		secrets []string
+		want    string
+	}{
+		{
+			name:    "long secret",
+			s:       "invalid token: hunter2hunter2, expected a jwt",
+			secrets: []string{"hunter2hunter2"},
+			want:    "invalid token: *****, expected a jwt",
+		},
+		{
+			name:    "short secret is not replaced within words",
+			s:       "loading flag: 'port': '1' is out of range: must be between 1024 and 65535",
+			secrets: []string{"1"},
+			want:    "loading flag: 'port': '*****' is out of range: must be between 1024 and 65535",
+		},
+		{
+			name:    "short secret in json",
+			s:       `invalid json: {"pin":"123"}`,
+			secrets: []string{"123"},
+			want:    `invalid json: {"pin":"*****"}`,
+		},
+		{
+			name:    "short secret as the whole text",
+			s:       "dev",
+			secrets: []string{"dev"},
+			want:    "*****",
+		},
+		{
+			name:    "empty secret",
+			s:       "no secrets here",
+			secrets: []string{""},
+			want:    "no secrets here",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := redact(tt.s, tt.secrets); got != tt.want {
+				t.Errorf("redact() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSecretFlag_String(t *testing.T) {
+	flag := SecretFlag{Name: "token", Value: "hunter2"}
+
+	for _, format := range []string{"%v", "%+v", "%#v", "%s"} {
+		for _, v := range []any{flag, &flag} {
+			if got := fmt.Sprintf(format, v); strings.Contains(got, "hunter2") {
+				t.Errorf("Sprintf(%q, %T) = %q, contains the secret", format, v, got)
+			}
+		}
+	}
+}
+
+func TestJSONFlag_SecretValues(t *testing.T) {
+	flag := &JSONFlag[map[string]string]{Name: "creds", Secret: true}
+
+	for _, raw := range []string{`{"key":"first"}`, `{"key":"second"}`} {
+		if _, err := flag.Load(true, &raw); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if got := flag.SecretValues(); len(got) != 1 || got[0] != `{"key":"second"}` {
+		t.Errorf("SecretValues() = %v, want only the last value loaded", got)
+	}
+}
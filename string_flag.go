@@ -2,7 +2,6 @@ package cli
 
 import (
 	"fmt"
-	"os"
 	"strings"
 )
 
@@ -13,9 +12,11 @@ type StringFlag struct {
 	Name           string
 	Alias          string
 	EnvVar         string
+	EnvVars        []string // fallback env vars, checked in order if EnvVar is not set
 	Description    string
-	AcceptedValues []string // if specified, only these values are accepted
-	Value          string   // can provide a default value here
+	AcceptedValues []string                 // if specified, only these values are accepted
+	Validate       func(value string) error // see ValidatedFlag
+	Value          string                   // can provide a default value here
 }
 
 func (flag *StringFlag) GetName() string {
@@ -26,15 +27,14 @@ func (flag *StringFlag) GetAlias() string {
 	return flag.Alias
 }
 
+func (flag *StringFlag) GetEnvVars() []string {
+	return envVarList(flag.EnvVar, flag.EnvVars)
+}
+
 func (flag *StringFlag) GetDescription() string {
 	desc := flag.Description
 
-	if flag.EnvVar != "" {
-		if desc != "" {
-			desc += "\n"
-		}
-		desc += "> env var: " + flag.EnvVar
-	}
+	desc = addEnvVarDesc(desc, flag.GetEnvVars())
 
 	if len(flag.AcceptedValues) != 0 {
 		desc += fmt.Sprintf("\n> accepted values: [%s]", strings.Join(flag.AcceptedValues, ", "))
@@ -43,6 +43,14 @@ func (flag *StringFlag) GetDescription() string {
 	return desc
 }
 
+func (flag *StringFlag) GetDefault() string {
+	return flag.Value
+}
+
+func (flag *StringFlag) GetPlaceholder() string {
+	return "string"
+}
+
 func (flag *StringFlag) Load(argFound bool, argVal *string) (loaded bool, err error) {
 	if argFound && argVal == nil {
 		return true, fmt.Errorf("no value found")
@@ -53,15 +61,13 @@ func (flag *StringFlag) Load(argFound bool, argVal *string) (loaded bool, err er
 		return true, flag.validateVal()
 	}
 
-	envVal := os.Getenv(flag.EnvVar)
-	if envVal != "" {
-		flag.Value = envVal
-		return true, flag.validateVal()
-	}
-
 	return false, nil
 }
 
+func (flag *StringFlag) ValidateValue() error {
+	return runValidate(flag.Validate, flag.Value)
+}
+
 func (flag *StringFlag) validateVal() error {
 	if len(flag.AcceptedValues) == 0 {
 		return nil
@@ -0,0 +1,99 @@
+package cli
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// StringMapFlag is a flag for key value pairs, eg: --labels=env=prod,team=core
+// The value can be provided by cli args or env var. CLI args take precedence.
+// Pairs are split on the Separator and surrounding whitespace is trimmed.
+// A JSON object of strings is also accepted, eg: from a config file.
+type StringMapFlag struct {
+	Name        string
+	Alias       string
+	EnvVar      string
+	EnvVars     []string // fallback env vars, checked in order if EnvVar is not set
+	Description string
+	Separator   string                              // separates pairs, defaults to ","
+	Validate    func(value map[string]string) error // see ValidatedFlag
+	Value       map[string]string                   // can provide a default value here
+}
+
+func (flag *StringMapFlag) GetName() string {
+	return flag.Name
+}
+
+func (flag *StringMapFlag) GetAlias() string {
+	return flag.Alias
+}
+
+func (flag *StringMapFlag) GetEnvVars() []string {
+	return envVarList(flag.EnvVar, flag.EnvVars)
+}
+
+func (flag *StringMapFlag) GetDescription() string {
+	desc := flag.Description
+
+	desc = addEnvVarDesc(desc, flag.GetEnvVars())
+
+	return desc
+}
+
+func (flag *StringMapFlag) GetDefault() string {
+	var pairs []string
+	for k, v := range flag.Value {
+		pairs = append(pairs, k+"="+v)
+	}
+	sort.Strings(pairs)
+
+	return strings.Join(pairs, flag.separator())
+}
+
+func (flag *StringMapFlag) GetPlaceholder() string {
+	return "key=value"
+}
+
+func (flag *StringMapFlag) Load(argFound bool, argVal *string) (loaded bool, err error) {
+	val, found, err := flagValue(flag.Name, argFound, argVal)
+	if !found || err != nil {
+		return found, err
+	}
+
+	if strings.HasPrefix(val, "{") {
+		var m map[string]string
+		if err := json.Unmarshal([]byte(val), &m); err != nil {
+			return false, fmt.Errorf("'%s' is not a valid json object of strings", val)
+		}
+
+		flag.Value = m
+		return true, nil
+	}
+
+	m := make(map[string]string)
+	for _, pair := range splitList(val, flag.separator()) {
+		k, v, ok := strings.Cut(pair, "=")
+		if !ok || strings.TrimSpace(k) == "" {
+			return false, fmt.Errorf("'%s' is not a valid key=value pair", pair)
+		}
+
+		m[strings.TrimSpace(k)] = strings.TrimSpace(v)
+	}
+
+	flag.Value = m
+	return true, nil
+}
+
+func (flag *StringMapFlag) ValidateValue() error {
+	return runValidate(flag.Validate, flag.Value)
+}
+
+func (flag *StringMapFlag) separator() string {
+	if flag.Separator == "" {
+		return ","
+	}
+
+	return flag.Separator
+}
@@ -0,0 +1,38 @@
+package cli
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestStringMapFlag_Load(t *testing.T) {
+	tests := []struct {
+		name    string
+		flag    StringMapFlag
+		in      string
+		want    map[string]string
+		wantErr bool
+	}{
+		{name: "single", in: "a=1", want: map[string]string{"a": "1"}},
+		{name: "comma separated", in: "a=1, b = 2", want: map[string]string{"a": "1", "b": "2"}},
+		{name: "empty value", in: "a=", want: map[string]string{"a": ""}},
+		{name: "value with equals", in: "q=x=y", want: map[string]string{"q": "x=y"}},
+		{name: "separator", flag: StringMapFlag{Separator: ";"}, in: "a=1,2;b=3", want: map[string]string{"a": "1,2", "b": "3"}},
+		{name: "json", in: `{"a": "1,2"}`, want: map[string]string{"a": "1,2"}},
+		{name: "missing equals", in: "a=1,b", wantErr: true},
+		{name: "empty key", in: "=1", wantErr: true},
+		{name: "invalid json", in: `{"a": 1}`, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.flag.Name = "labels"
+			_, err := tt.flag.Load(true, &tt.in)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Load() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !tt.wantErr && !reflect.DeepEqual(tt.flag.Value, tt.want) {
+				t.Errorf("Load() Value = %v, want %v", tt.flag.Value, tt.want)
+			}
+		})
+	}
+}
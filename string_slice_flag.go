@@ -0,0 +1,95 @@
+package cli
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// StringSliceFlag is a flag for a list of strings, eg: --tags=a,b,c
+// The value can be provided by cli args or env var. CLI args take precedence.
+// Values are split on the Separator and surrounding whitespace is trimmed.
+// A JSON array of strings is also accepted, eg: from a config file.
+type StringSliceFlag struct {
+	Name        string
+	Alias       string
+	EnvVar      string
+	EnvVars     []string // fallback env vars, checked in order if EnvVar is not set
+	Description string
+	Separator   string                     // defaults to ","
+	Validate    func(value []string) error // see ValidatedFlag
+	Value       []string                   // can provide a default value here
+}
+
+func (flag *StringSliceFlag) GetName() string {
+	return flag.Name
+}
+
+func (flag *StringSliceFlag) GetAlias() string {
+	return flag.Alias
+}
+
+func (flag *StringSliceFlag) GetEnvVars() []string {
+	return envVarList(flag.EnvVar, flag.EnvVars)
+}
+
+func (flag *StringSliceFlag) GetDescription() string {
+	desc := flag.Description
+
+	desc = addEnvVarDesc(desc, flag.GetEnvVars())
+
+	return desc
+}
+
+func (flag *StringSliceFlag) GetDefault() string {
+	return strings.Join(flag.Value, flag.separator())
+}
+
+func (flag *StringSliceFlag) GetPlaceholder() string {
+	return "list"
+}
+
+func (flag *StringSliceFlag) Load(argFound bool, argVal *string) (loaded bool, err error) {
+	val, found, err := flagValue(flag.Name, argFound, argVal)
+	if !found || err != nil {
+		return found, err
+	}
+
+	if strings.HasPrefix(val, "[") {
+		var list []string
+		if err := json.Unmarshal([]byte(val), &list); err != nil {
+			return false, fmt.Errorf("'%s' is not a valid json array of strings", val)
+		}
+
+		flag.Value = list
+		return true, nil
+	}
+
+	flag.Value = splitList(val, flag.separator())
+	return true, nil
+}
+
+func (flag *StringSliceFlag) ValidateValue() error {
+	return runValidate(flag.Validate, flag.Value)
+}
+
+func (flag *StringSliceFlag) separator() string {
+	if flag.Separator == "" {
+		return ","
+	}
+
+	return flag.Separator
+}
+
+// splitList splits a list on the separator, trimming whitespace and ignoring empty items
+func splitList(val string, sep string) []string {
+	var list []string
+	for _, item := range strings.Split(val, sep) {
+		item = strings.TrimSpace(item)
+		if item != "" {
+			list = append(list, item)
+		}
+	}
+
+	return list
+}
@@ -0,0 +1,35 @@
+package cli
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestStringSliceFlag_Load(t *testing.T) {
+	tests := []struct {
+		name    string
+		flag    StringSliceFlag
+		in      string
+		want    []string
+		wantErr bool
+	}{
+		{name: "single", in: "a", want: []string{"a"}},
+		{name: "comma separated", in: "a,b,c", want: []string{"a", "b", "c"}},
+		{name: "whitespace and empty items", in: " a , ,b,", want: []string{"a", "b"}},
+		{name: "separator", flag: StringSliceFlag{Separator: ";"}, in: "a,b;c", want: []string{"a,b", "c"}},
+		{name: "json", in: `["a,b", " c "]`, want: []string{"a,b", " c "}},
+		{name: "invalid json", in: `["a", 1]`, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.flag.Name = "tags"
+			_, err := tt.flag.Load(true, &tt.in)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Load() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !tt.wantErr && !reflect.DeepEqual(tt.flag.Value, tt.want) {
+				t.Errorf("Load() Value = %q, want %q", tt.flag.Value, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,205 @@
+package cli
+
+import (
+	"os"
+	"sync"
+)
+
+// ValueSource provides the raw values of flags, eg: from env vars or a config file.
+// The sources of an app are configured with App.ValueSources, in order of precedence:
+// a flag is loaded from the first source that has a value for it.
+type ValueSource interface {
+	// Source is reported as the provenance of values loaded from the source, see Context.Provenance.
+	Source() Source
+
+	// Lookup returns the raw value of the flag, if the source has one.
+	// key describes where the value was found, eg: the name of an env var.
+	// val is nil if the flag was provided without a value, eg: --verbose
+	Lookup(ctx SourceContext, fl Flag) (key string, val *string, found bool, err error)
+}
+
+// SourceContext is passed to ValueSource.Lookup
+type SourceContext struct {
+	CmdPath  []string // names of the sub commands leading to the command, eg: [db migrate]
+	FlagArgs []string // the flag args of the command
+
+	lookupEnv  func(name string) (string, bool) // see App.LookupEnv
+	autoEnvVar func(fl Flag) string             // see App.AutoEnv
+	profile    *configFile                      // the active profile, if any (see ProfileSource)
+	config     *configFile                      // the config file, if any (see ConfigSource)
+}
+
+// LookupEnv reads an env var, see App.LookupEnv
+func (ctx SourceContext) LookupEnv(name string) (string, bool) {
+	if ctx.lookupEnv == nil {
+		return os.LookupEnv(name)
+	}
+
+	return ctx.lookupEnv(name)
+}
+
+// EnvVars returns the env vars a flag is loaded from: the env vars it specifies (see EnvVarFlag),
+// or the name derived for it (see App.AutoEnv)
+func (ctx SourceContext) EnvVars(fl Flag) []string {
+	if ef, ok := flagAs[EnvVarFlag](fl); ok && len(ef.GetEnvVars()) > 0 {
+		return ef.GetEnvVars()
+	}
+
+	if ctx.autoEnvVar != nil {
+		if name := ctx.autoEnvVar(fl); name != "" {
+			return []string{name}
+		}
+	}
+
+	return nil
+}
+
+// defaultValueSources are used if App.ValueSources is not specified
+func defaultValueSources() []ValueSource {
+	return []ValueSource{ArgsSource(), EnvSource(), ProfileSource(), ConfigSource()}
+}
+
+// ArgsSource loads flags from cli args, eg: --db-conn=postgres://localhost.
+// Flags that implement ArgsFlag are looked up with LookupArgs.
+func ArgsSource() ValueSource {
+	return argsSource{}
+}
+
+type argsSource struct{}
+
+func (argsSource) Source() Source {
+	return SourceArgs
+}
+
+func (argsSource) Lookup(ctx SourceContext, fl Flag) (key string, val *string, found bool, err error) {
+	if af, ok := flagAs[ArgsFlag](fl); ok {
+		return af.LookupArgs(ctx.FlagArgs)
+	}
+
+	found, val, err = LoadFlagFromArgs(fl.GetName(), fl.GetAlias(), ctx.FlagArgs)
+	if err != nil || !found {
+		return "", nil, false, err
+	}
+
+	return findFlagArg(fl, ctx.FlagArgs), val, true, nil
+}
+
+// EnvSource loads flags from the env vars they specify (see EnvVarFlag), or the env var derived by App.AutoEnv
+func EnvSource() ValueSource {
+	return envSource{}
+}
+
+type envSource struct{}
+
+func (envSource) Source() Source {
+	return SourceEnv
+}
+
+func (envSource) Lookup(ctx SourceContext, fl Flag) (key string, val *string, found bool, err error) {
+	return lookupEnvVars(ctx.LookupEnv, ctx.EnvVars(fl))
+}
+
+// ProfileSource loads flags from the active profile, see App.Profiles
+func ProfileSource() ValueSource {
+	return profileSource{}
+}
+
+type profileSource struct{}
+
+func (profileSource) Source() Source {
+	return SourceProfile
+}
+
+func (profileSource) Lookup(ctx SourceContext, fl Flag) (key string, val *string, found bool, err error) {
+	return lookupConfigFile(ctx.profile, ctx.CmdPath, fl)
+}
+
+// ConfigSource loads flags from the config file, see App.ConfigFile
+func ConfigSource() ValueSource {
+	return configSource{}
+}
+
+type configSource struct{}
+
+func (configSource) Source() Source {
+	return SourceConfig
+}
+
+func (configSource) Lookup(ctx SourceContext, fl Flag) (key string, val *string, found bool, err error) {
+	return lookupConfigFile(ctx.config, ctx.CmdPath, fl)
+}
+
+func lookupConfigFile(cfg *configFile, cmdPath []string, fl Flag) (key string, val *string, found bool, err error) {
+	if cfg == nil {
+		return "", nil, false, nil
+	}
+
+	key, raw, found, err := cfg.lookup(cmdPath, fl.GetName())
+	if err != nil || !found {
+		return "", nil, false, err
+	}
+
+	return cfg.path + ":" + key, &raw, true, nil
+}
+
+// DotEnvSource loads flags from the env vars defined in dotenv files (see App.DotEnvFiles for the format),
+// without modifying the environment of the process. Files that don't exist are skipped.
+// When multiple files define the same env var, the first file takes precedence.
+func DotEnvSource(paths ...string) ValueSource {
+	return &dotEnvSource{paths: paths}
+}
+
+type dotEnvSource struct {
+	paths []string
+
+	mu   sync.Mutex
+	vars map[string]string // nil until the files have been read
+}
+
+func (src *dotEnvSource) Source() Source {
+	return SourceDotEnv
+}
+
+func (src *dotEnvSource) Lookup(ctx SourceContext, fl Flag) (key string, val *string, found bool, err error) {
+	names := ctx.EnvVars(fl)
+	if len(names) == 0 {
+		return "", nil, false, nil
+	}
+
+	vars, err := src.load(ctx.LookupEnv)
+	if err != nil {
+		return "", nil, false, err
+	}
+
+	return lookupEnvVars(MapEnv(vars), names)
+}
+
+// load reads the dotenv files the first time a value is looked up.
+// If the files can't be read, they are read again by the next lookup.
+func (src *dotEnvSource) load(lookupEnv func(name string) (string, bool)) (map[string]string, error) {
+	src.mu.Lock()
+	defer src.mu.Unlock()
+
+	if src.vars != nil {
+		return src.vars, nil
+	}
+
+	vars, err := readDotEnvFiles(src.paths, lookupEnv)
+	if err != nil {
+		return nil, err
+	}
+
+	src.vars = vars
+	return vars, nil
+}
+
+// hasValueSource returns true if a source of type T is in sources
+func hasValueSource[T ValueSource](sources []ValueSource) bool {
+	for _, src := range sources {
+		if _, ok := src.(T); ok {
+			return true
+		}
+	}
+
+	return false
+}
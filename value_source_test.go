@@ -0,0 +1,162 @@
+package cli
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// mapSource is a custom value source, eg: a secrets manager
+type mapSource map[string]string
+
+func (src mapSource) Source() Source {
+	return "vault"
+}
+
+func (src mapSource) Lookup(ctx SourceContext, fl Flag) (key string, val *string, found bool, err error) {
+	if fl.GetName() == "fail" {
+		return "", nil, false, errors.New("vault is sealed")
+	}
+
+	v, found := src[fl.GetName()]
+	return "secret/" + fl.GetName(), &v, found, nil
+}
+
+func TestCmd_run_valueSources(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.json")
+	if err := os.WriteFile(path, []byte(`{"region": "config-region", "env": "config-env"}`), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	t.Setenv("TEST_SOURCES_REGION", "env-region")
+	t.Setenv("TEST_SOURCES_TOKEN", "env-token")
+	t.Setenv("TEST_SOURCES_VERBOSE", "3")
+
+	regionFlag := &StringFlag{Name: "region", EnvVar: "TEST_SOURCES_REGION"}
+	tokenFlag := &SecretFlag{Name: "token", EnvVar: "TEST_SOURCES_TOKEN"}
+	envFlag := &StringFlag{Name: "env"}
+	verboseFlag := &CountFlag{Name: "verbose", EnvVar: "TEST_SOURCES_VERBOSE"}
+	portFlag := &IntFlag{Name: "port", Value: 8080}
+
+	var actionCtx *Context
+	cmd := Cmd{
+		Name:     "myapp",
+		OptFlags: []Flag{regionFlag, tokenFlag, envFlag, verboseFlag, portFlag},
+		ActionCtx: func(ctx *Context) error {
+			actionCtx = ctx
+			return nil
+		},
+		app: &App{
+			Name:       "myapp",
+			ConfigFile: path,
+			// the config file takes precedence over env vars
+			ValueSources: []ValueSource{ArgsSource(), mapSource{"token": "vault-token"}, ConfigSource(), EnvSource()},
+		},
+	}
+
+	if err := cmd.run([]string{"--env=args-env"}, []string{"myapp"}); err != nil {
+		t.Fatalf("run() unexpected error = %v", err)
+	}
+
+	tests := []struct {
+		name     string
+		fl       Flag
+		got      any
+		want     any
+		wantProv Provenance
+	}{
+		{"config before env", regionFlag, regionFlag.Value, "config-region", Provenance{Source: SourceConfig, Key: path + ":region", Raw: "config-region"}},
+		{"custom source", tokenFlag, tokenFlag.Value, "vault-token", Provenance{Source: "vault", Key: "secret/token", Raw: "vault-token"}},
+		{"args first", envFlag, envFlag.Value, "args-env", Provenance{Source: SourceArgs, Key: "--env", Raw: "args-env"}},
+		{"args flag from env", verboseFlag, verboseFlag.Value, 3, Provenance{Source: SourceEnv, Key: "TEST_SOURCES_VERBOSE", Raw: "3"}},
+		{"default", portFlag, portFlag.Value, 8080, Provenance{Source: SourceDefault, Raw: "8080"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if tt.got != tt.want {
+				t.Errorf("Value = %v, want %v", tt.got, tt.want)
+			}
+			if got := actionCtx.Provenance(tt.fl); got != tt.wantProv {
+				t.Errorf("Provenance() = %v, want %v", got, tt.wantProv)
+			}
+		})
+	}
+}
+
+func TestCmd_run_valueSourcesWithoutEnv(t *testing.T) {
+	t.Setenv("TEST_SOURCES_NO_ENV", "env-value")
+
+	fl := &StringFlag{Name: "name", EnvVar: "TEST_SOURCES_NO_ENV", Value: "default"}
+	cmd := Cmd{
+		Name:     "myapp",
+		OptFlags: []Flag{fl},
+		Action:   func(args map[string]string) {},
+		app:      &App{Name: "myapp", ValueSources: []ValueSource{ArgsSource()}},
+	}
+
+	if err := cmd.run(nil, []string{"myapp"}); err != nil {
+		t.Fatalf("run() unexpected error = %v", err)
+	}
+
+	if fl.Value != "default" {
+		t.Errorf("env vars are not loaded without EnvSource: Value = %v, want %v", fl.Value, "default")
+	}
+}
+
+func TestCmd_run_valueSourceError(t *testing.T) {
+	cmd := Cmd{
+		Name:     "myapp",
+		OptFlags: []Flag{&StringFlag{Name: "fail"}},
+		Action:   func(args map[string]string) {},
+		app:      &App{Name: "myapp", ValueSources: []ValueSource{ArgsSource(), mapSource{}}},
+	}
+
+	if err := cmd.run(nil, []string{"myapp"}); err == nil {
+		t.Fatal("run() expected error")
+	}
+}
+
+func TestDotEnvSource(t *testing.T) {
+	dir := t.TempDir()
+	local := filepath.Join(dir, ".env.local")
+	if err := os.WriteFile(local, []byte("TEST_DOTENV_SOURCE_REGION=eu-west-1\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	shared := filepath.Join(dir, ".env")
+	if err := os.WriteFile(shared, []byte("TEST_DOTENV_SOURCE_REGION=us-east-1\nTEST_DOTENV_SOURCE_HOST=localhost\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	src := DotEnvSource(local, shared, filepath.Join(dir, "missing.env"))
+
+	tests := []struct {
+		name    string
+		fl      Flag
+		wantKey string
+		wantVal string
+		found   bool
+	}{
+		{"first file wins", &StringFlag{Name: "region", EnvVar: "TEST_DOTENV_SOURCE_REGION"}, "TEST_DOTENV_SOURCE_REGION", "eu-west-1", true},
+		{"second file", &StringFlag{Name: "host", EnvVar: "TEST_DOTENV_SOURCE_HOST"}, "TEST_DOTENV_SOURCE_HOST", "localhost", true},
+		{"not defined", &StringFlag{Name: "port", EnvVar: "TEST_DOTENV_SOURCE_PORT"}, "", "", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			key, val, found, err := src.Lookup(SourceContext{}, tt.fl)
+			if err != nil {
+				t.Fatalf("Lookup() unexpected error = %v", err)
+			}
+			if val == nil {
+				val = new(string)
+			}
+			if key != tt.wantKey || *val != tt.wantVal || found != tt.found {
+				t.Errorf("Lookup() = %q, %q, %v, want %q, %q, %v", key, *val, found, tt.wantKey, tt.wantVal, tt.found)
+			}
+		})
+	}
+
+	if _, ok := os.LookupEnv("TEST_DOTENV_SOURCE_REGION"); ok {
+		t.Errorf("DotEnvSource should not modify the environment")
+	}
+}